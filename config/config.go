@@ -1,9 +1,12 @@
 package config
 
 import (
-	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
 	"github.com/joho/godotenv"
 )
 
@@ -16,6 +19,49 @@ type config struct {
 	OpenrouterBaseUrl    string
 	OpenrouterApiKey     string
 	OpenrouterImageModel string
+	TranslationModels    []string
+
+	HTTPAddr        string
+	DisconnectGrace time.Duration
+	APIToken        string
+	PairPhone       string
+
+	RevokeTranslations bool
+	MessageRetention   time.Duration
+
+	// ReactionFlags maps a reaction emoji to the language code it translates to
+	ReactionFlags map[string]string
+	// ReactionUsers lists phone numbers besides the owner allowed to trigger
+	// translations with reactions
+	ReactionUsers []string
+
+	// PersistRateLimits keeps rate limit buckets in the database so limits
+	// survive restarts
+	PersistRateLimits bool
+
+	// CommandWorkers bounds how many messages are handled at once across all
+	// chats; CommandQueueDepth bounds how many may wait per chat
+	CommandWorkers    int
+	CommandQueueDepth int
+	DrainTimeout      time.Duration
+
+	// LogLevel is one of debug, info, warn or error; LogFormat is console or
+	// json
+	LogLevel  string
+	LogFormat string
+
+	// UILanguage is the language of the bot's replies in chats and for
+	// senders that haven't chosen one with /uilang
+	UILanguage string
+
+	// AfkCooldown is how long a sender isn't answered again while AFK
+	AfkCooldown time.Duration
+
+	// DownloadWorkers bounds how many downloads run at once; further ones
+	// wait in a queue. DownloadUserLimit bounds how many one user may have
+	// queued or running.
+	DownloadWorkers   int
+	DownloadUserLimit int
 }
 
 var (
@@ -24,7 +70,7 @@ var (
 
 func init() {
 	if err := godotenv.Load(); err != nil {
-		log.Printf("error loading env variables, proceeding without it %v\n", err)
+		logging.Log.Info().Err(err).Msg("No .env file loaded, using the environment only")
 	}
 
 	AppConfig.GeminiAPIKey = os.Getenv("GEMINI_API_KEY")
@@ -36,4 +82,104 @@ func init() {
 	AppConfig.OpenrouterModel = os.Getenv("OPENROUTER_MODEL")
 	AppConfig.OpenrouterApiKey = os.Getenv("OPENROUTER_APIKEY")
 	AppConfig.OpenrouterImageModel = os.Getenv("OPENROUTER_IMAGE_MODEL")
+	AppConfig.TranslationModels = getList("TRANSLATION_MODELS")
+
+	AppConfig.HTTPAddr = getEnv("HTTP_ADDR", ":8080")
+	AppConfig.DisconnectGrace = getDuration("DISCONNECT_GRACE", 5*time.Minute)
+	AppConfig.APIToken = os.Getenv("API_TOKEN")
+	AppConfig.PairPhone = os.Getenv("PAIR_PHONE")
+
+	AppConfig.RevokeTranslations = getBool("REVOKE_TRANSLATIONS", false)
+	AppConfig.MessageRetention = getDuration("MESSAGE_RETENTION", 7*24*time.Hour)
+
+	AppConfig.ReactionFlags = getMap("REACTION_FLAGS", "🇷🇺=ru,🇮🇳=hi,🇬🇧=en")
+	AppConfig.ReactionUsers = getList("REACTION_USERS")
+
+	AppConfig.PersistRateLimits = getBool("PERSIST_RATE_LIMITS", false)
+
+	AppConfig.LogLevel = getEnv("LOG_LEVEL", "info")
+	AppConfig.LogFormat = getEnv("LOG_FORMAT", "console")
+
+	AppConfig.UILanguage = strings.ToLower(getEnv("UI_LANGUAGE", "en"))
+
+	AppConfig.AfkCooldown = getDuration("AFK_COOLDOWN", 30*time.Minute)
+
+	AppConfig.DownloadWorkers = getInt("DOWNLOAD_WORKERS", 1)
+	AppConfig.DownloadUserLimit = getInt("DOWNLOAD_USER_LIMIT", 3)
+
+	AppConfig.CommandWorkers = getInt("COMMAND_WORKERS", 8)
+	AppConfig.CommandQueueDepth = getInt("COMMAND_QUEUE_DEPTH", 10)
+	AppConfig.DrainTimeout = getDuration("DRAIN_TIMEOUT", 30*time.Second)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logging.Log.Warn().Str("key", key).Str("value", value).Dur("default", fallback).Msg("Invalid duration, using the default")
+		return fallback
+	}
+	return d
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		logging.Log.Warn().Str("key", key).Str("value", value).Int("default", fallback).Msg("Invalid integer, using the default")
+		return fallback
+	}
+	return n
+}
+
+func getBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		logging.Log.Warn().Str("key", key).Str("value", value).Bool("default", fallback).Msg("Invalid boolean, using the default")
+		return fallback
+	}
+	return b
+}
+
+// getList reads a comma separated list, dropping empty entries.
+func getList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getMap reads comma separated key=value pairs.
+func getMap(key, fallback string) map[string]string {
+	value := getEnv(key, fallback)
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			logging.Log.Warn().Str("key", key).Str("entry", pair).Msg("Ignoring invalid entry")
+			continue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
 }
@@ -0,0 +1,304 @@
+package cmdframework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+)
+
+// Flag describes a named option such as --quality=720 or -q 720. Bool flags
+// don't take a value: --audio sets them, --audio=false clears them.
+type Flag struct {
+	Name        string
+	Short       string
+	Type        ParameterType
+	Default     interface{}
+	Description string
+}
+
+// Flags holds parsed flag values, with defaults filled in.
+type Flags struct {
+	values map[string]interface{}
+	set    map[string]bool
+}
+
+// Has reports whether the flag was given explicitly.
+func (f Flags) Has(name string) bool {
+	return f.set[name]
+}
+
+func (f Flags) Get(name string) interface{} {
+	return f.values[name]
+}
+
+func (f Flags) String(name string) string {
+	v, _ := f.values[name].(string)
+	return v
+}
+
+func (f Flags) Int(name string) int {
+	v, _ := f.values[name].(int)
+	return v
+}
+
+func (f Flags) Float(name string) float64 {
+	v, _ := f.values[name].(float64)
+	return v
+}
+
+func (f Flags) Bool(name string) bool {
+	v, _ := f.values[name].(bool)
+	return v
+}
+
+func (f Flags) Duration(name string) time.Duration {
+	v, _ := f.values[name].(time.Duration)
+	return v
+}
+
+// SplitArgs splits a command line the way a shell would: whitespace separates
+// arguments, single quotes are literal, double quotes allow \" and \\ escapes
+// and a backslash outside quotes escapes the next character. The curly quotes
+// phone keyboards like to insert are treated like their ASCII counterparts.
+// Free text is never rejected: an apostrophe inside a word (don't), a quote
+// that is never closed and a trailing backslash are kept as typed.
+func SplitArgs(s string) []string {
+	tokens := splitTokens(s)
+	args := make([]string, len(tokens))
+	for i, t := range tokens {
+		args[i] = t.text
+	}
+	return args
+}
+
+// RawAfter returns raw without its first n arguments and the whitespace
+// after them, keeping quotes, escapes and line breaks of the rest as typed.
+func RawAfter(raw string, n int) string {
+	if n <= 0 {
+		return strings.TrimSpace(raw)
+	}
+	tokens := splitTokens(raw)
+	if n > len(tokens) {
+		return ""
+	}
+	return strings.TrimSpace(raw[tokens[n-1].end:])
+}
+
+// RawRest returns the untouched text after the first n positional arguments
+// of the command, e.g. a message with its line breaks and quotes. Flags,
+// which come before the positional arguments, are skipped too.
+func (c *Context) RawRest(n int) string {
+	flagTokens := len(splitTokens(c.RawArgs)) - len(c.Args)
+	return RawAfter(c.RawArgs, flagTokens+n)
+}
+
+type token struct {
+	text string
+	// end is the byte offset just after the token in the input
+	end int
+}
+
+// splitTokens implements SplitArgs. An unterminated quote is retried as a
+// literal character.
+func splitTokens(s string) []token {
+	literal := make(map[int]bool)
+	for {
+		tokens, open := tokenize(s, literal)
+		if open < 0 {
+			return tokens
+		}
+		literal[open] = true
+	}
+}
+
+// tokenize splits s, treating the quotes at the offsets in literal as plain
+// characters. It returns the offset of a quote left open, or -1.
+func tokenize(s string, literal map[int]bool) ([]token, int) {
+	var (
+		tokens    []token
+		current   strings.Builder
+		inArg     bool
+		quote     rune
+		quoteAt   int
+		escaped   bool
+		prevSpace = true
+	)
+
+	for i, r := range s {
+		isLiteral := literal[i]
+		switch {
+		case escaped:
+			// Within double quotes only quotes and backslashes are escaped
+			if quote == '"' && r != '"' && r != '”' && r != '\\' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' || r == '’' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"', '”':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case (r == '"' || r == '“' || r == '”') && !isLiteral:
+			quote = '"'
+			quoteAt = i
+			inArg = true
+		case (r == '\'' || r == '‘') && !isLiteral && prevSpace:
+			// Only a quote starting a word quotes; inside one it's an apostrophe
+			quote = '\''
+			quoteAt = i
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				tokens = append(tokens, token{text: current.String(), end: i})
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+		prevSpace = quote == 0 && !escaped && unicode.IsSpace(r)
+	}
+
+	if quote != 0 {
+		return nil, quoteAt
+	}
+	if escaped {
+		current.WriteRune('\\')
+	}
+	if inArg {
+		tokens = append(tokens, token{text: current.String(), end: len(s)})
+	}
+	return tokens, -1
+}
+
+// ParseArgs tokenizes the raw arguments of a command and, when the command
+// declares flags, separates them from the positional arguments.
+func ParseArgs(raw string, meta *Metadata) ([]string, Flags, error) {
+	args := SplitArgs(raw)
+	if len(meta.Flags) == 0 {
+		return args, Flags{}, nil
+	}
+	return parseFlags(args, meta.Flags)
+}
+
+// parseFlags separates flags from the positional arguments. Flags come
+// first: everything from the first positional argument on, or after "--",
+// is positional, so free text may contain words starting with a dash.
+func parseFlags(args []string, defs []Flag) ([]string, Flags, error) {
+	flags := Flags{
+		values: make(map[string]interface{}),
+		set:    make(map[string]bool),
+	}
+	for _, def := range defs {
+		if def.Default != nil {
+			flags.values[def.Name] = def.Default
+		}
+	}
+
+	lookup := func(name string, short bool) (Flag, bool) {
+		for _, def := range defs {
+			if (!short && def.Name == name) || (short && def.Short != "" && def.Short == name) {
+				return def, true
+			}
+		}
+		return Flag{}, false
+	}
+
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		var name string
+		var short bool
+		switch {
+		case strings.HasPrefix(arg, "--") && len(arg) > 2:
+			name = arg[2:]
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !unicode.IsDigit(rune(arg[1])):
+			name, short = arg[1:], true
+		default:
+			positional = append(positional, args[i:]...)
+			return positional, flags, nil
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		def, ok := lookup(name, short)
+		if !ok {
+			return nil, Flags{}, argError("args.unknown_flag", "unknown flag %s", arg)
+		}
+
+		if !hasValue {
+			if def.Type == BoolParam {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			} else {
+				return nil, Flags{}, argError("args.flag_needs_value", "flag --%s needs a value", def.Name)
+			}
+		}
+
+		parsed, err := parseValue(value, def.Type)
+		if err != nil {
+			return nil, Flags{}, argError("args.invalid_flag", "invalid value for --%s: %v", def.Name, err)
+		}
+		flags.values[def.Name] = parsed
+		flags.set[def.Name] = true
+	}
+
+	return positional, flags, nil
+}
+
+// ArgError is an error in the arguments or flags a user gave a command. In
+// shows it in the user's language.
+type ArgError struct {
+	key     string
+	message string
+	args    []interface{}
+}
+
+func argError(key, message string, args ...interface{}) *ArgError {
+	return &ArgError{key: key, message: message, args: args}
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf(e.message, e.args...)
+}
+
+// In is the error message in the language of l.
+func (e *ArgError) In(l *i18n.Localizer) string {
+	return l.T(e.key, e.message, e.args...)
+}
+
+// ErrorText is the message of err, in the language of l if it is an
+// [ArgError].
+func ErrorText(l *i18n.Localizer, err error) string {
+	var argErr *ArgError
+	if errors.As(err, &argErr) {
+		return argErr.In(l)
+	}
+	return err.Error()
+}
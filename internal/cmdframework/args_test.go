@@ -0,0 +1,195 @@
+package cmdframework
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"whitespace", "a  b\tc\nd", []string{"a", "b", "c", "d"}},
+		{"empty", "   ", []string{}},
+		{"double quotes", `"hello world" x`, []string{"hello world", "x"}},
+		{"single quotes are literal", `'a \" b' x`, []string{`a \" b`, "x"}},
+		{"escaped quote in double quotes", `"say \"hi\""`, []string{`say "hi"`}},
+		{"other escapes in double quotes are kept", `"a\nb"`, []string{`a\nb`}},
+		{"escaped space", `a\ b c`, []string{"a b", "c"}},
+		{"empty quotes", `"" x`, []string{"", "x"}},
+		{"quotes inside a word", `foo"bar baz"qux`, []string{"foobar bazqux"}},
+		{"apostrophe", "don't stop", []string{"don't", "stop"}},
+		{"curly apostrophe", "don’t stop", []string{"don’t", "stop"}},
+		{"curly double quotes", "“hello world” x", []string{"hello world", "x"}},
+		{"curly single quotes", "‘hello world’ x", []string{"hello world", "x"}},
+		{"unterminated double quote", `say "hello world`, []string{"say", `"hello`, "world"}},
+		{"unterminated single quote", "'hello world", []string{"'hello", "world"}},
+		{"apostrophe and unterminated quote", `it's "open`, []string{"it's", `"open`}},
+		{"unterminated quote after a closed one", `"a b" "c d`, []string{"a b", `"c`, "d"}},
+		{"trailing backslash", `abc\`, []string{`abc\`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitArgs(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		n    int
+		want string
+	}{
+		{"nothing skipped", "  a b  ", 0, "a b"},
+		{"keeps line breaks", "one two\nthree", 1, "two\nthree"},
+		{"skips quoted argument", `"one two" "three" rest`, 2, "rest"},
+		{"keeps quotes of the rest", `one "two three"`, 1, `"two three"`},
+		{"past the end", "one two", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RawAfter(tt.raw, tt.n); got != tt.want {
+				t.Errorf("RawAfter(%q, %d) = %q, want %q", tt.raw, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	defs := []Flag{
+		{Name: "quality", Short: "q", Type: IntParam, Default: 720},
+		{Name: "audio", Short: "a", Type: BoolParam},
+		{Name: "format", Type: StringParam},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		values     map[string]interface{}
+		set        []string
+	}{
+		{
+			name:       "defaults",
+			args:       []string{"url"},
+			positional: []string{"url"},
+			values:     map[string]interface{}{"quality": 720, "audio": false},
+		},
+		{
+			name:       "long flag with value",
+			args:       []string{"--quality=480", "url"},
+			positional: []string{"url"},
+			values:     map[string]interface{}{"quality": 480},
+			set:        []string{"quality"},
+		},
+		{
+			name:       "short flag with separate value",
+			args:       []string{"-q", "360", "url"},
+			positional: []string{"url"},
+			values:     map[string]interface{}{"quality": 360},
+			set:        []string{"quality"},
+		},
+		{
+			name:       "bool flag",
+			args:       []string{"-a", "url"},
+			positional: []string{"url"},
+			values:     map[string]interface{}{"audio": true},
+			set:        []string{"audio"},
+		},
+		{
+			name:       "bool flag cleared",
+			args:       []string{"--audio=false", "url"},
+			positional: []string{"url"},
+			values:     map[string]interface{}{"audio": false},
+			set:        []string{"audio"},
+		},
+		{
+			name:       "flags stop at the first positional argument",
+			args:       []string{"url", "--audio", "-q", "1"},
+			positional: []string{"url", "--audio", "-q", "1"},
+			values:     map[string]interface{}{"audio": false, "quality": 720},
+		},
+		{
+			name:       "double dash",
+			args:       []string{"--format", "mp4", "--", "--audio"},
+			positional: []string{"--audio"},
+			values:     map[string]interface{}{"format": "mp4", "audio": false},
+			set:        []string{"format"},
+		},
+		{
+			name:       "negative number is positional",
+			args:       []string{"-5", "x"},
+			positional: []string{"-5", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positional, flags, err := parseFlags(tt.args, defs)
+			if err != nil {
+				t.Fatalf("parseFlags(%q) failed: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) {
+				t.Errorf("positional = %q, want %q", positional, tt.positional)
+			}
+			for name, want := range tt.values {
+				var got interface{}
+				switch want.(type) {
+				case bool:
+					got = flags.Bool(name)
+				default:
+					got = flags.Get(name)
+				}
+				if got != want {
+					t.Errorf("flag %s = %v, want %v", name, got, want)
+				}
+			}
+			for _, name := range tt.set {
+				if !flags.Has(name) {
+					t.Errorf("flag %s isn't marked as given", name)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	defs := []Flag{
+		{Name: "quality", Short: "q", Type: IntParam},
+		{Name: "format", Type: StringParam},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		key  string
+	}{
+		{"unknown long flag", []string{"--nope"}, "args.unknown_flag"},
+		{"unknown short flag", []string{"-x", "url"}, "args.unknown_flag"},
+		{"missing value", []string{"--format"}, "args.flag_needs_value"},
+		{"invalid value", []string{"--quality=high", "url"}, "args.invalid_flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseFlags(tt.args, defs)
+			var argErr *ArgError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("parseFlags(%q) error = %v, want an ArgError", tt.args, err)
+			}
+			if argErr.key != tt.key {
+				t.Errorf("error key = %s, want %s", argErr.key, tt.key)
+			}
+		})
+	}
+}
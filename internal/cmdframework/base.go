@@ -29,7 +29,7 @@ type ParameterizedCommand struct {
 func (c *ParameterizedCommand) Execute(ctx *Context) error {
 	params, err := c.parseParameters(ctx)
 	if err != nil {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, fmt.Sprintf("❌ %s", err.Error()))
+		return ctx.Handler.SendResponse(ctx.MessageInfo, Error(ErrorText(ctx.Locale, err)))
 	}
 
 	return c.Handler(ctx, params)
@@ -47,8 +47,11 @@ func (c *ParameterizedCommand) parseParameters(ctx *Context) (map[string]interfa
 
 		if i < len(ctx.Args) {
 			value = ctx.Args[i]
+			if param.Rest {
+				value = strings.Join(ctx.Args[i:], " ")
+			}
 		} else if param.Required {
-			return nil, fmt.Errorf("missing required parameter: %s", param.Name)
+			return nil, argError("args.missing_param", "missing required parameter: %s", param.Name)
 		} else if param.Default != nil {
 			params[param.Name] = param.Default
 			continue
@@ -58,13 +61,13 @@ func (c *ParameterizedCommand) parseParameters(ctx *Context) (map[string]interfa
 
 		if param.Validator != nil {
 			if err := param.Validator(value); err != nil {
-				return nil, fmt.Errorf("invalid %s: %v", param.Name, err)
+				return nil, argError("args.invalid_param", "invalid %s: %v", param.Name, err)
 			}
 		}
 
 		parsedValue, err := parseValue(value, param.Type)
 		if err != nil {
-			return nil, fmt.Errorf("invalid %s: %v", param.Name, err)
+			return nil, argError("args.invalid_param", "invalid %s: %v", param.Name, err)
 		}
 
 		params[param.Name] = parsedValue
@@ -137,39 +140,11 @@ func RequireOwner() Middleware {
 			next: next,
 			fn: func(ctx *Context, next Command) error {
 				if !ctx.MessageInfo.IsFromMe {
-					return ctx.Handler.SendResponse(ctx.MessageInfo, "❌ This command requires owner permissions")
+					return ctx.Handler.SendResponse(ctx.MessageInfo,
+						Error(ctx.T("owner.required", "This command requires owner permissions")))
 				}
 				return next.Execute(ctx)
 			},
 		}
 	}
 }
-
-func RateLimit(perMinute int) Middleware {
-	lastUsed := make(map[string]time.Time)
-
-	return func(next Command) Command {
-		return &middlewareCommand{
-			next: next,
-			fn: func(ctx *Context, next Command) error {
-				key := ctx.MessageInfo.Sender.String()
-				now := time.Now()
-
-				if last, exists := lastUsed[key]; exists {
-					elapsed := now.Sub(last)
-					minInterval := time.Minute / time.Duration(perMinute)
-
-					if elapsed < minInterval {
-						remaining := minInterval - elapsed
-						return ctx.Handler.SendResponse(ctx.MessageInfo,
-							fmt.Sprintf("⏱️ Please wait %d seconds before using this command again",
-								int(remaining.Seconds())))
-					}
-				}
-
-				lastUsed[key] = now
-				return next.Execute(ctx)
-			},
-		}
-	}
-}
@@ -2,7 +2,10 @@ package cmdframework
 
 import (
 	"context"
+	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+	"github.com/rs/zerolog"
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
@@ -21,8 +24,14 @@ type Metadata struct {
 	Usage        string
 	Examples     []string
 	RequireOwner bool
+	MinRole      Role
 	Hidden       bool
+	Essential    bool   // can't be disabled per chat
+	HelpGroup    string // commands sharing it get one line in the help overview
 	Parameters   []Parameter
+	Flags        []Flag
+	RateLimit    *Limit
+	Timeout      time.Duration // DefaultTimeout if zero
 }
 
 type Parameter struct {
@@ -32,6 +41,9 @@ type Parameter struct {
 	Required    bool
 	Default     interface{}
 	Validator   func(value string) error
+	// Rest makes a string parameter consume the remaining arguments, so it
+	// must be the last one.
+	Rest bool
 }
 
 type ParameterType int
@@ -53,19 +65,39 @@ type Context struct {
 	Command     string
 	Args        []string
 	RawArgs     string
+	Flags       Flags
+	// Role of the sender in this chat, resolved by the dispatcher
+	Role Role
+	// Logger carries the command, chat and sender as fields
+	Logger zerolog.Logger
+	// Locale renders replies in the UI language of the sender or chat
+	Locale *i18n.Localizer
 
 	// Services
 	Handler HandlerInterface
+	Jobs    *JobManager
+}
+
+// T returns the reply text of key in the UI language of the context; see
+// [i18n.Localizer.T].
+func (c *Context) T(key, english string, args ...interface{}) string {
+	return c.Locale.T(key, english, args...)
 }
 
 type HandlerInterface interface {
 	SendResponse(msgInfo types.MessageInfo, text string) error
+	// SendResponseWithID is SendResponse that also returns the ID of the
+	// message holding the response, so it can be edited later.
+	SendResponseWithID(msgInfo types.MessageInfo, text string) (types.MessageID, error)
 	SendMedia(msgInfo types.MessageInfo, mediaType MediaType, data []byte, caption string) error
 	SendImage(msgInfo types.MessageInfo, upload UploadResponse, caption string) error
 	SendVideo(msgInfo types.MessageInfo, upload UploadResponse, caption string) error
 	SendDocument(msgInfo types.MessageInfo, upload UploadResponse, caption string) error
 	EditMessage(msgInfo types.MessageInfo, newText string) error
 	EditMessageWithOriginal(msgInfo types.MessageInfo, newText string, originalMsg *waProto.Message) error
+	// SendTranslation delivers a translation like SendResponse and links it to
+	// sourceID so it is updated when the source message is edited.
+	SendTranslation(msgInfo types.MessageInfo, sourceID types.MessageID, targetLang, text string) error
 	GetClient() ClientInterface
 	GetTranslator() TranslatorInterface
 	GetImageGenerator() ImageGeneratorInterface
@@ -0,0 +1,259 @@
+package cmdframework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+)
+
+// Group is a command made of subcommands, e.g. /model set and /model get.
+// Subcommands are ordinary commands (and may be groups themselves) with their
+// own metadata, aliases, flags and permissions.
+type Group struct {
+	Meta Metadata
+	// Default names the subcommand run when none is given; without it the
+	// group's help is shown instead.
+	Default string
+
+	children *Registry
+}
+
+func NewGroup(meta Metadata, subcommands ...Command) (*Group, error) {
+	g := &Group{Meta: meta, children: NewRegistry()}
+	for _, sub := range subcommands {
+		if err := g.children.Register(sub); err != nil {
+			return nil, fmt.Errorf("group %s: %w", meta.Name, err)
+		}
+	}
+	return g, nil
+}
+
+func (g *Group) Subcommands() *Registry {
+	return g.children
+}
+
+func (g *Group) Metadata() *Metadata {
+	return &g.Meta
+}
+
+func (g *Group) Execute(ctx *Context) error {
+	// ctx.Command may use an alias; show the canonical name instead
+	parts := strings.Fields(strings.TrimPrefix(ctx.Command, "/"))
+	if len(parts) == 0 {
+		parts = []string{g.Meta.Name}
+	}
+	parts[len(parts)-1] = g.Meta.Name
+	path := "/" + strings.Join(parts, " ")
+
+	name := g.Default
+	args := ctx.Args
+	rawArgs := ctx.RawArgs
+	if len(args) > 0 {
+		name = args[0]
+		args = args[1:]
+		rawArgs = trimFirstWord(rawArgs)
+	}
+
+	if name == "" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, g.children.renderHelp(ctx.Locale, path, g))
+	}
+
+	sub, exists := g.children.Get(name)
+	if !exists {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, Error(ctx.T("group.unknown",
+			"Unknown subcommand `%s` for *%s*\nAvailable: %s",
+			name, path, strings.Join(g.children.visibleNames(), ", "))))
+	}
+
+	subCtx := *ctx
+	subCtx.Command = strings.TrimPrefix(path, "/") + " " + sub.Metadata().Name
+	subCtx.Args = args
+	subCtx.RawArgs = rawArgs
+
+	if flags := sub.Metadata().Flags; len(flags) > 0 {
+		parsedArgs, parsedFlags, err := parseFlags(args, flags)
+		if err != nil {
+			return ctx.Handler.SendResponse(ctx.MessageInfo, Error(fmt.Sprintf("%s\n%s", ErrorText(ctx.Locale, err),
+				ctx.T("usage.line", "Usage: `%s`", usageFor("/"+subCtx.Command, sub.Metadata())))))
+		}
+		subCtx.Args = parsedArgs
+		subCtx.Flags = parsedFlags
+	}
+
+	return sub.Execute(&subCtx)
+}
+
+// subcommandsOf returns the child registry of a group, looking through any
+// middleware wrapped around it.
+func subcommandsOf(cmd Command) *Registry {
+	if g := groupOf(cmd); g != nil {
+		return g.children
+	}
+	return nil
+}
+
+// groupOf returns cmd as a group, looking through any middleware wrapped
+// around it, or nil if it isn't one.
+func groupOf(cmd Command) *Group {
+	for {
+		switch c := cmd.(type) {
+		case *Group:
+			return c
+		case *middlewareCommand:
+			cmd = c.next
+		default:
+			return nil
+		}
+	}
+}
+
+// timeoutFor returns the timeout of the command that runs for args: for a
+// group the subcommand args select, which inherits the timeout of its
+// enclosing groups unless it sets its own.
+func timeoutFor(cmd Command, args []string) time.Duration {
+	timeout := cmd.Metadata().Timeout
+	for g := groupOf(cmd); g != nil; g = groupOf(cmd) {
+		name := g.Default
+		if len(args) > 0 {
+			name, args = args[0], args[1:]
+		}
+		sub, exists := g.children.Get(name)
+		if !exists {
+			break
+		}
+		if sub.Metadata().Timeout > 0 {
+			timeout = sub.Metadata().Timeout
+		}
+		cmd = sub
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return timeout
+}
+
+// visibleNames lists the non-hidden commands of a registry, sorted.
+func (r *Registry) visibleNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.commands))
+	for name, cmd := range r.commands {
+		if !cmd.Metadata().Hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderHelp shows a group's description and its subcommand tree in the
+// language of l.
+func (r *Registry) renderHelp(l *i18n.Localizer, path string, group Command) string {
+	meta := group.Metadata()
+
+	var sb strings.Builder
+	sb.WriteString(l.T("help.command", "*Command:* *%s*", path) + "\n")
+	if meta.Description != "" {
+		sb.WriteString(l.T("help.description", "*Description:* %s", meta.Description) + "\n")
+	}
+	sb.WriteString("\n" + l.T("help.subcommands", "*Subcommands:*") + "\n")
+	r.writeTree(&sb, path, 0)
+	sb.WriteString("\n" + l.T("help.subcommand_hint", "Send `/help %s <subcommand>` for details", strings.TrimPrefix(path, "/")))
+	return sb.String()
+}
+
+func (r *Registry) writeTree(sb *strings.Builder, path string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, name := range r.visibleNames() {
+		cmd, _ := r.Get(name)
+		meta := cmd.Metadata()
+
+		sb.WriteString(fmt.Sprintf("%s• `%s %s`", indent, path, name))
+		if meta.Description != "" {
+			sb.WriteString(fmt.Sprintf(" - %s", meta.Description))
+		}
+		if meta.RequiredRole() > RoleEveryone {
+			sb.WriteString(" 🔒")
+		}
+		sb.WriteString("\n")
+
+		if children := subcommandsOf(cmd); children != nil {
+			children.writeTree(sb, path+" "+name, depth+1)
+		}
+	}
+}
+
+// Walk calls fn for every command in the registry and, depth first, for every
+// subcommand of groups. path is the space separated command path without the
+// leading slash, e.g. "model set".
+func (r *Registry) Walk(fn func(path string, cmd Command)) {
+	r.walk("", fn)
+}
+
+func (r *Registry) walk(prefix string, fn func(path string, cmd Command)) {
+	for name, cmd := range r.GetAll() {
+		path := strings.TrimSpace(prefix + " " + name)
+		fn(path, cmd)
+		if children := subcommandsOf(cmd); children != nil {
+			children.walk(path, fn)
+		}
+	}
+}
+
+// ApplyMiddleware wraps every command, including subcommands of groups, for
+// which match returns true.
+func (r *Registry) ApplyMiddleware(match func(meta *Metadata) bool, middleware ...Middleware) {
+	for name, cmd := range r.GetAll() {
+		if children := subcommandsOf(cmd); children != nil {
+			children.ApplyMiddleware(match, middleware...)
+		}
+		if match(cmd.Metadata()) {
+			_ = r.UpdateCommand(name, WithMiddleware(cmd, middleware...))
+		}
+	}
+}
+
+// Resolve follows a space separated command path such as "model set" and
+// returns the deepest command it names.
+func (r *Registry) Resolve(path string) (Command, bool) {
+	cmd, _, exists := r.resolve(path)
+	return cmd, exists
+}
+
+// resolve is Resolve that also returns the canonical path, with aliases
+// replaced by command names, e.g. "/temp set" for "temperature set".
+func (r *Registry) resolve(path string) (Command, string, bool) {
+	parts := strings.Fields(strings.TrimPrefix(strings.TrimSpace(path), "/"))
+	if len(parts) == 0 {
+		return nil, "", false
+	}
+
+	registry := r
+	var cmd Command
+	var canonical []string
+	for _, part := range parts {
+		if registry == nil {
+			return nil, "", false
+		}
+		var exists bool
+		if cmd, exists = registry.Get(part); !exists {
+			return nil, "", false
+		}
+		canonical = append(canonical, cmd.Metadata().Name)
+		registry = subcommandsOf(cmd)
+	}
+	return cmd, "/" + strings.Join(canonical, " "), true
+}
+
+func trimFirstWord(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+	}
+	return ""
+}
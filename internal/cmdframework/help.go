@@ -0,0 +1,265 @@
+package cmdframework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+)
+
+// HelpPageSize is the number of lines on a page of help output.
+const HelpPageSize = 30
+
+// HelpFilter decides which commands help output lists, typically the ones
+// the caller may run. A nil filter lists every visible command.
+type HelpFilter func(cmd Command) bool
+
+// RoleFilter lists the commands role is allowed to run.
+func RoleFilter(role Role) HelpFilter {
+	return func(cmd Command) bool {
+		return role >= cmd.Metadata().RequiredRole()
+	}
+}
+
+func (f HelpFilter) accepts(cmd Command) bool {
+	return !cmd.Metadata().Hidden && (f == nil || f(cmd))
+}
+
+// GenerateHelp lists all visible commands by category on a single page.
+func (r *Registry) GenerateHelp() string {
+	return "📋 *Available Commands*\n\n" + strings.Join(r.HelpLines(nil, nil), "\n")
+}
+
+// HelpLines lists the commands accepted by filter by category, with category
+// titles in the language of l. Commands that share a HelpGroup are collapsed
+// into one line.
+func (r *Registry) HelpLines(l *i18n.Localizer, filter HelpFilter) []string {
+	var lines []string
+	for _, category := range r.helpCategories() {
+		commands := r.commandsIn(category, filter)
+		if len(commands) == 0 {
+			continue
+		}
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("*%s*", categoryTitle(l, category)))
+		lines = append(lines, collapseHelpGroups(commands, category)...)
+	}
+	return lines
+}
+
+// CategoryHelpLines lists every command of category accepted by filter, and
+// the category's title in the language of l. It reports false if there is no
+// such category.
+func (r *Registry) CategoryHelpLines(l *i18n.Localizer, category string, filter HelpFilter) (string, []string, bool) {
+	for _, c := range r.helpCategories() {
+		if !strings.EqualFold(c, category) {
+			continue
+		}
+		commands := r.commandsIn(c, filter)
+		lines := make([]string, len(commands))
+		for i, cmd := range commands {
+			lines[i] = helpLine(cmd)
+		}
+		return categoryTitle(l, c), lines, true
+	}
+	return "", nil, false
+}
+
+// SearchHelpLines lists the commands and subcommands accepted by filter whose
+// name, aliases, description or examples contain term.
+func (r *Registry) SearchHelpLines(term string, filter HelpFilter) []string {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+
+	type match struct {
+		path string
+		cmd  Command
+	}
+	var matches []match
+	r.Walk(func(path string, cmd Command) {
+		if !filter.accepts(cmd) {
+			return
+		}
+		// Subcommands are only found if their top-level command is listed
+		if top, exists := r.Get(strings.Fields(path)[0]); !exists || !filter.accepts(top) {
+			return
+		}
+		if helpMatches(path, cmd.Metadata(), term) {
+			matches = append(matches, match{path: path, cmd: cmd})
+		}
+	})
+	sort.Slice(matches, func(i, j int) bool { return matches[i].path < matches[j].path })
+
+	// Collapse large families such as the language commands
+	groups := make(map[string][]Command)
+	for _, m := range matches {
+		if group := m.cmd.Metadata().HelpGroup; group != "" && !strings.Contains(m.path, " ") {
+			groups[group] = append(groups[group], m.cmd)
+		}
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		meta := m.cmd.Metadata()
+		if members := groups[meta.HelpGroup]; len(members) > 3 && !strings.Contains(m.path, " ") {
+			if !seen[meta.HelpGroup] {
+				seen[meta.HelpGroup] = true
+				lines = append(lines, helpGroupLine(meta.HelpGroup, members, meta.Category))
+			}
+			continue
+		}
+		if strings.Contains(m.path, " ") {
+			line := fmt.Sprintf("• `/%s`", m.path)
+			if meta.Description != "" {
+				line += " - " + meta.Description
+			}
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, helpLine(m.cmd))
+	}
+	return lines
+}
+
+// Paginate splits lines into pages of up to size lines and returns page
+// (1-based, clamped to the valid range) along with its number and the page
+// count. Pages don't end on a category heading.
+func Paginate(lines []string, page, size int) ([]string, int, int) {
+	if size <= 0 {
+		size = HelpPageSize
+	}
+
+	var bounds [][2]int
+	for start := 0; start < len(lines); {
+		end := min(start+size, len(lines))
+		if end < len(lines) && end-start > 2 && strings.HasPrefix(lines[end-1], "*") {
+			end--
+		}
+		bounds = append(bounds, [2]int{start, end})
+		start = end
+	}
+	if len(bounds) == 0 {
+		return nil, 1, 1
+	}
+
+	page = max(1, min(page, len(bounds)))
+	result := lines[bounds[page-1][0]:bounds[page-1][1]]
+	for len(result) > 0 && result[0] == "" {
+		result = result[1:]
+	}
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result, page, len(bounds)
+}
+
+// helpCategories returns the categories in display order, with commands
+// without a category under "Other" at the end.
+func (r *Registry) helpCategories() []string {
+	categories := r.GetCategories()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, cmd := range r.commands {
+		if cmd.Metadata().Category == "" {
+			return append(categories, "")
+		}
+	}
+	return categories
+}
+
+// commandsIn returns the commands of category accepted by filter, sorted by
+// name.
+func (r *Registry) commandsIn(category string, filter HelpFilter) []Command {
+	r.mu.RLock()
+	var commands []Command
+	for _, cmd := range r.commands {
+		if cmd.Metadata().Category == category {
+			commands = append(commands, cmd)
+		}
+	}
+	r.mu.RUnlock()
+
+	result := commands[:0]
+	for _, cmd := range commands {
+		if filter.accepts(cmd) {
+			result = append(result, cmd)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Metadata().Name < result[j].Metadata().Name })
+	return result
+}
+
+func categoryTitle(l *i18n.Localizer, category string) string {
+	if category == "" {
+		return l.T("help.other_commands", "Other Commands")
+	}
+	return category
+}
+
+// collapseHelpGroups renders commands, replacing the members of every
+// HelpGroup with a single line.
+func collapseHelpGroups(commands []Command, category string) []string {
+	groups := make(map[string][]Command)
+	for _, cmd := range commands {
+		if group := cmd.Metadata().HelpGroup; group != "" {
+			groups[group] = append(groups[group], cmd)
+		}
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	for _, cmd := range commands {
+		group := cmd.Metadata().HelpGroup
+		if members := groups[group]; group != "" && len(members) > 1 {
+			if !seen[group] {
+				seen[group] = true
+				lines = append(lines, helpGroupLine(group, members, category))
+			}
+			continue
+		}
+		lines = append(lines, helpLine(cmd))
+	}
+	return lines
+}
+
+func helpLine(cmd Command) string {
+	meta := cmd.Metadata()
+	line := fmt.Sprintf("• */%s*", meta.Name)
+	if children := subcommandsOf(cmd); children != nil {
+		line += fmt.Sprintf(" [%s]", strings.Join(children.visibleNames(), "|"))
+	}
+	if meta.Description != "" {
+		line += " - " + meta.Description
+	}
+	return line
+}
+
+func helpGroupLine(group string, members []Command, category string) string {
+	examples := make([]string, 0, 3)
+	for _, cmd := range members[:min(3, len(members))] {
+		examples = append(examples, "/"+cmd.Metadata().Name)
+	}
+	line := fmt.Sprintf("• *%s* - %d commands, e.g. %s", group, len(members), strings.Join(examples, ", "))
+	if category != "" {
+		line += fmt.Sprintf(" (see `/help %s`)", strings.ToLower(category))
+	}
+	return line
+}
+
+func helpMatches(path string, meta *Metadata, term string) bool {
+	fields := append([]string{path, meta.Description}, meta.Aliases...)
+	fields = append(fields, meta.Examples...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+	return false
+}
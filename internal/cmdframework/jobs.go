@@ -0,0 +1,488 @@
+package cmdframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ErrJobRunning is returned by JobManager.Start when an exclusive job of the
+// same kind is already running.
+var ErrJobRunning = errors.New("job already running")
+
+// JobError is reported to the ErrorReporter of the JobManager when a job
+// fails.
+type JobError struct {
+	JobID string
+	Err   error
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job #%s: %v", e.JobID, e.Err)
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultJobTimeout applies to jobs that don't set JobOptions.Timeout.
+const DefaultJobTimeout = 15 * time.Minute
+
+// JobOptions configures a job started with JobManager.Go.
+type JobOptions struct {
+	// Exclusive allows only one running job with the same key at a time
+	Exclusive string
+	// Status is sent as the job's status message; later updates edit it.
+	// Without it the command's own message is used as status message.
+	Status string
+	// StatusID adopts an existing message as status message instead of
+	// sending Status, e.g. when resuming a job after a restart
+	StatusID types.MessageID
+	// Timeout starts when the job runs, not while it waits in Queue
+	Timeout time.Duration
+	// Queue makes the job wait for its turn in a JobQueue
+	Queue *JobQueue
+	// OnDone is called when the job ends, even if it never ran
+	OnDone func(job *Job)
+}
+
+// Job is a cancellable long-running piece of work started by a command.
+type Job struct {
+	ID        string
+	Command   string
+	Chat      types.JID
+	Sender    types.JID
+	SenderAlt types.JID
+	StartedAt time.Time
+
+	cancel    context.CancelFunc
+	timeout   time.Duration
+	exclusive string
+	queue     *JobQueue
+	turn      chan struct{}
+	waited    bool
+	onDone    func(job *Job)
+	finished  chan struct{}
+	manager   *JobManager
+	handler   HandlerInterface
+	msgInfo   types.MessageInfo
+	locale    *i18n.Localizer
+	// origin is the context of the command that started the job, for
+	// error reports
+	origin Context
+
+	interrupted atomic.Bool
+
+	mu       sync.Mutex
+	ctx      context.Context
+	statusID types.MessageID
+	progress string
+	// err is the job's result, see Err
+	err error
+}
+
+// Context is cancelled when the job is cancelled or times out.
+func (j *Job) Context() context.Context {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.ctx
+}
+
+// Done is closed once the job has ended and its OnDone has returned.
+func (j *Job) Done() <-chan struct{} {
+	return j.finished
+}
+
+// Err is why the job failed, or nil if it succeeded. Jobs that were stopped
+// report context.Canceled or ErrTimeout. Err is only meaningful once the job
+// has ended, e.g. in OnDone.
+func (j *Job) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Origin is the context of the command that started the job.
+func (j *Job) Origin() *Context {
+	origin := j.origin
+	return &origin
+}
+
+func (j *Job) setErr(err error) {
+	j.mu.Lock()
+	j.err = err
+	j.mu.Unlock()
+}
+
+// Cancelled reports whether the job was cancelled with /cancel or by
+// JobManager.CancelAll.
+func (j *Job) Cancelled() bool {
+	return errors.Is(j.Context().Err(), context.Canceled)
+}
+
+// Interrupted reports whether the job was stopped by JobManager.CancelAll
+// rather than by its user, e.g. because the bot is shutting down.
+func (j *Job) Interrupted() bool {
+	return j.interrupted.Load()
+}
+
+// Queued reports whether the job is waiting for its turn in its queue.
+func (j *Job) Queued() bool {
+	return j.queue != nil && j.queue.Position(j) > 0
+}
+
+// Sleep pauses for d and reports false if the job was stopped meanwhile.
+func (j *Job) Sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-j.Context().Done():
+		return false
+	}
+}
+
+// StatusID is the message that shows the job's progress.
+func (j *Job) StatusID() types.MessageID {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.statusID
+}
+
+// Progress returns the last status set with Update.
+func (j *Job) Progress() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.progress
+}
+
+// Update records the job's progress and shows it in the status message.
+// Until the status message has been sent the progress is only recorded.
+func (j *Job) Update(text string) error {
+	j.mu.Lock()
+	j.progress = text
+	msgInfo := j.msgInfo
+	msgInfo.ID = j.statusID
+	j.mu.Unlock()
+
+	if msgInfo.ID == "" {
+		return nil
+	}
+	return j.handler.EditMessage(msgInfo, text)
+}
+
+func (j *Job) queuedStatus(position, total int) string {
+	return Processing(j.locale.T("queue.position", "Queued, position %d of %d", position, total))
+}
+
+func (j *Job) done() {
+	defer j.manager.running.Done()
+	j.cancel()
+	j.manager.remove(j)
+	if j.onDone != nil {
+		j.onDone(j)
+	}
+	if j.manager.onFinish != nil {
+		j.manager.onFinish(j)
+	}
+	close(j.finished)
+}
+
+// isStartedBy reports whether jid is the user who started the job.
+func (j *Job) isStartedBy(jids ...types.JID) bool {
+	for _, jid := range jids {
+		if jid.IsEmpty() {
+			continue
+		}
+		if jid.User == j.Sender.User || (!j.SenderAlt.IsEmpty() && jid.User == j.SenderAlt.User) {
+			return true
+		}
+	}
+	return false
+}
+
+// JobManager tracks the running jobs of an account. It is safe for
+// concurrent use.
+type JobManager struct {
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	queues map[string]*JobQueue
+	nextID int
+	// reporter is told about failed and panicked jobs; it may be nil
+	reporter ErrorReporter
+	onFinish func(job *Job)
+
+	// running counts the jobs until they have finished reporting, for Wait
+	running sync.WaitGroup
+}
+
+// NewJobManager creates a job manager reporting failed jobs to reporter,
+// which may be nil.
+func NewJobManager(reporter ErrorReporter) *JobManager {
+	return &JobManager{jobs: make(map[string]*Job), reporter: reporter}
+}
+
+// OnFinish makes the manager call fn whenever a job has ended, after the
+// job's own OnDone. It must be set before any job is started.
+func (m *JobManager) OnFinish(fn func(job *Job)) {
+	m.onFinish = fn
+}
+
+// Go runs work as a job of the command in ctx on its own goroutine, so the
+// chat isn't blocked while it runs. Errors and panics of work are shown in
+// the job's status message as a generic failure; the details go to the
+// logs and the ErrorReporter. With JobOptions.Queue the job first waits for
+// its turn, showing its position in the status message.
+func (m *JobManager) Go(ctx *Context, opts JobOptions, work func(job *Job) error) (*Job, error) {
+	m.mu.Lock()
+	if opts.Exclusive != "" {
+		for _, job := range m.jobs {
+			if job.exclusive == opts.Exclusive {
+				m.mu.Unlock()
+				return nil, fmt.Errorf("%w: #%s", ErrJobRunning, job.ID)
+			}
+		}
+	}
+
+	// The job outlives the command, so it isn't bound to its context. The
+	// timeout is applied once the job runs.
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultJobTimeout
+	}
+	jobCtx, cancel := context.WithCancel(context.Background())
+
+	m.nextID++
+	job := &Job{
+		ID:        strconv.Itoa(m.nextID),
+		Command:   ctx.Command,
+		Chat:      ctx.MessageInfo.Chat,
+		Sender:    ctx.MessageInfo.Sender.ToNonAD(),
+		SenderAlt: ctx.MessageInfo.SenderAlt.ToNonAD(),
+		StartedAt: time.Now(),
+		cancel:    cancel,
+		timeout:   timeout,
+		exclusive: opts.Exclusive,
+		queue:     opts.Queue,
+		onDone:    opts.OnDone,
+		finished:  make(chan struct{}),
+		manager:   m,
+		handler:   ctx.Handler,
+		msgInfo:   ctx.MessageInfo,
+		locale:    ctx.Locale,
+		origin:    *ctx,
+		ctx:       jobCtx,
+		progress:  opts.Status,
+	}
+	if opts.Status == "" && opts.StatusID == "" {
+		job.statusID = ctx.MessageInfo.ID
+	}
+	m.jobs[job.ID] = job
+	m.running.Add(1)
+	m.mu.Unlock()
+
+	status := opts.Status
+	if opts.Queue != nil && opts.Queue.enqueue(job) {
+		job.waited = true
+		status = job.queuedStatus(opts.Queue.Position(job), opts.Queue.Waiting())
+		job.mu.Lock()
+		job.progress = status
+		job.mu.Unlock()
+	}
+
+	switch {
+	case opts.StatusID != "":
+		job.mu.Lock()
+		job.statusID = opts.StatusID
+		job.mu.Unlock()
+		if status != "" {
+			_ = job.Update(status)
+		}
+	case status != "":
+		id, err := ctx.Handler.SendResponseWithID(ctx.MessageInfo, status)
+		if err == nil && id != "" {
+			job.mu.Lock()
+			job.statusID = id
+			job.mu.Unlock()
+		}
+	}
+
+	go job.run(work, opts.Status)
+	return job, nil
+}
+
+func (j *Job) run(work func(job *Job) error, status string) {
+	defer j.done()
+	defer func() {
+		if v := recover(); v != nil {
+			stack := debug.Stack()
+			logging.Log.Error().Str("job", j.ID).Str("command", j.Command).Interface("panic", v).Bytes("stack", stack).Msg("Job panicked")
+			_ = j.Update(Error(j.locale.T("job.panic", "Something went wrong while running the job")))
+			panicErr := &PanicError{Value: v, Stack: stack}
+			j.setErr(panicErr)
+			j.report(&JobError{JobID: j.ID, Err: panicErr})
+		}
+	}()
+
+	if j.queue != nil {
+		defer j.queue.release(j)
+		if !j.waitTurn(status) {
+			j.reportStop()
+			return
+		}
+	}
+
+	j.mu.Lock()
+	ctx, cancel := context.WithTimeout(j.ctx, j.timeout)
+	j.ctx = ctx
+	j.mu.Unlock()
+	defer cancel()
+
+	err := work(j)
+	switch {
+	case j.Cancelled() || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		j.reportStop()
+	case err != nil:
+		j.setErr(err)
+		logging.Log.Warn().Err(err).Str("job", j.ID).Str("command", j.Command).Msg("Job failed")
+		_ = j.Update(Error(j.locale.T("job.failed", "/%s failed", j.Command)))
+		j.report(&JobError{JobID: j.ID, Err: err})
+	}
+}
+
+// report passes err to the ErrorReporter of the job's manager.
+func (j *Job) report(err error) {
+	if j.manager.reporter != nil {
+		j.manager.reporter.ReportCommandError(&j.origin, err)
+	}
+}
+
+// waitTurn waits until the job may run in its queue. A job that had to wait
+// gets its status back and its user is told it started, as the position
+// may have been shown for a while.
+func (j *Job) waitTurn(status string) bool {
+	if !j.queue.wait(j) {
+		return false
+	}
+	if !j.waited {
+		return true
+	}
+
+	if status != "" {
+		_ = j.Update(status)
+	}
+	// The account's own command message would be edited instead of answered
+	if !j.msgInfo.IsFromMe {
+		_ = j.handler.SendResponse(j.msgInfo, Info(j.locale.T("queue.started", "Your /%s is starting", j.Command)))
+	}
+	return true
+}
+
+// reportStop shows why a job stopped before finishing its work.
+func (j *Job) reportStop() {
+	if errors.Is(j.Context().Err(), context.DeadlineExceeded) {
+		j.setErr(fmt.Errorf("%w after %s", ErrTimeout, j.timeout))
+	} else {
+		j.setErr(context.Canceled)
+	}
+
+	switch {
+	case j.Interrupted():
+		_ = j.Update(Warning(j.locale.T("job.interrupted", "/%s was interrupted", j.Command)))
+	case j.Cancelled():
+		_ = j.Update(Warning(j.locale.T("job.cancelled", "Cancelled /%s", j.Command)))
+	case errors.Is(j.Context().Err(), context.DeadlineExceeded):
+		_ = j.Update(Error(j.locale.T("job.timeout", "/%s took too long and was stopped", j.Command)))
+	}
+}
+
+func (m *JobManager) remove(job *Job) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs[job.ID] == job {
+		delete(m.jobs, job.ID)
+	}
+}
+
+// List returns the running jobs, oldest first.
+func (m *JobManager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].StartedAt.Before(jobs[k].StartedAt) })
+	return jobs
+}
+
+// Get returns the job with the given ID; a leading "#" is ignored.
+func (m *JobManager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(id) > 0 && id[0] == '#' {
+		id = id[1:]
+	}
+	job, exists := m.jobs[id]
+	return job, exists
+}
+
+// ByMessage finds the job whose status message, or command message, is id.
+func (m *JobManager) ByMessage(chat types.JID, id types.MessageID) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.Chat == chat && (job.StatusID() == id || job.msgInfo.ID == id) {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+// Cancel cancels a job if role or the user in msgInfo may do so: the owner
+// can cancel every job, anyone else only the jobs they started.
+func (m *JobManager) Cancel(job *Job, msgInfo types.MessageInfo, role Role) error {
+	if role < RoleOwner && !job.isStartedBy(msgInfo.Sender.ToNonAD(), msgInfo.SenderAlt.ToNonAD()) {
+		return fmt.Errorf("job #%s was started by someone else", job.ID)
+	}
+	job.cancel()
+	return nil
+}
+
+// CancelAll cancels every running and queued job, e.g. on shutdown. The jobs
+// report themselves as interrupted rather than cancelled.
+func (m *JobManager) CancelAll() {
+	for _, job := range m.List() {
+		job.interrupted.Store(true)
+		job.cancel()
+	}
+}
+
+// Wait waits until every job has finished, including updating its status
+// message, or ctx is done.
+func (m *JobManager) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d jobs still running: %w", len(m.List()), ctx.Err())
+	}
+}
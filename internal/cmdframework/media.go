@@ -99,6 +99,12 @@ func (m *MediaUploader) UploadAndSendDocument(ctx context.Context, to types.JID,
 		mimeType = "video/" + strings.TrimPrefix(ext, ".")
 	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
 		mimeType = "image/" + strings.TrimPrefix(ext, ".")
+	case ".mp3":
+		mimeType = "audio/mpeg"
+	case ".opus", ".ogg":
+		mimeType = "audio/ogg"
+	case ".m4a":
+		mimeType = "audio/mp4"
 	case ".pdf":
 		mimeType = "application/pdf"
 	case ".txt":
@@ -123,6 +129,33 @@ func (m *MediaUploader) UploadAndSendDocument(ctx context.Context, to types.JID,
 	return err
 }
 
+func (m *MediaUploader) UploadAndSendAudio(ctx context.Context, to types.JID, audioData []byte, mimeType string, voiceNote bool) error {
+	uploaded, err := m.client.Upload(ctx, audioData, MediaAudio)
+	if err != nil {
+		return fmt.Errorf("failed to upload audio: %w", err)
+	}
+
+	if mimeType == "" {
+		mimeType = "audio/mpeg"
+	}
+
+	msg := &waProto.Message{
+		AudioMessage: &waProto.AudioMessage{
+			Mimetype:      proto.String(mimeType),
+			PTT:           proto.Bool(voiceNote),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		},
+	}
+
+	_, err = m.client.SendMessage(ctx, to, msg)
+	return err
+}
+
 func DownloadMedia(url string) ([]byte, error) {
 	resp, err := http.Get(url)
 	if err != nil {
@@ -0,0 +1,138 @@
+package cmdframework
+
+import (
+	"slices"
+	"sync"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// JobQueue runs the jobs started with it in the order they were started, at
+// most a fixed number at a time. Waiting jobs show their position in their
+// status message, and their timeout only starts once they run. Get a queue
+// with JobManager.Queue.
+type JobQueue struct {
+	workers int
+
+	mu      sync.Mutex
+	running []*Job
+	waiting []*Job
+}
+
+// Queue returns the queue called name, creating it with room for workers
+// jobs at a time on first use.
+func (m *JobManager) Queue(name string, workers int) *JobQueue {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if q, ok := m.queues[name]; ok {
+		return q
+	}
+	if m.queues == nil {
+		m.queues = make(map[string]*JobQueue)
+	}
+	q := &JobQueue{workers: max(workers, 1)}
+	m.queues[name] = q
+	return q
+}
+
+// Waiting returns the number of jobs waiting for their turn.
+func (q *JobQueue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting)
+}
+
+// CountStartedBy returns the number of running and waiting jobs started by
+// the sender of msgInfo.
+func (q *JobQueue) CountStartedBy(msgInfo types.MessageInfo) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, job := range append(slices.Clone(q.running), q.waiting...) {
+		if job.isStartedBy(msgInfo.Sender.ToNonAD(), msgInfo.SenderAlt.ToNonAD()) {
+			n++
+		}
+	}
+	return n
+}
+
+// Position returns the 1-based place of job among the waiting jobs, or 0 if
+// it isn't waiting.
+func (q *JobQueue) Position(job *Job) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Index(q.waiting, job) + 1
+}
+
+// enqueue adds job to the queue, letting it run right away if a worker is
+// free, and reports whether it has to wait.
+func (q *JobQueue) enqueue(job *Job) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job.turn = make(chan struct{})
+	if len(q.running) < q.workers && len(q.waiting) == 0 {
+		q.running = append(q.running, job)
+		close(job.turn)
+		return false
+	}
+	q.waiting = append(q.waiting, job)
+	return true
+}
+
+// wait blocks until it's the turn of job and reports false if the job was
+// stopped while waiting. A job whose turn came must call release when done.
+func (q *JobQueue) wait(job *Job) bool {
+	select {
+	case <-job.turn:
+		return true
+	case <-job.Context().Done():
+	}
+
+	q.mu.Lock()
+	i := slices.Index(q.waiting, job)
+	if i >= 0 {
+		q.waiting = slices.Delete(q.waiting, i, i+1)
+	}
+	q.mu.Unlock()
+
+	if i >= 0 {
+		q.showPositions()
+	}
+	return false
+}
+
+// release frees the worker of job, if it has one, and lets the next jobs
+// run.
+func (q *JobQueue) release(job *Job) {
+	q.mu.Lock()
+	if i := slices.Index(q.running, job); i >= 0 {
+		q.running = slices.Delete(q.running, i, i+1)
+	}
+	var started []*Job
+	for len(q.running) < q.workers && len(q.waiting) > 0 {
+		next := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		q.running = append(q.running, next)
+		close(next.turn)
+		started = append(started, next)
+	}
+	q.mu.Unlock()
+
+	if len(started) > 0 {
+		q.showPositions()
+	}
+}
+
+// showPositions updates the status messages of the waiting jobs.
+func (q *JobQueue) showPositions() {
+	q.mu.Lock()
+	waiting := slices.Clone(q.waiting)
+	q.mu.Unlock()
+
+	for i, job := range waiting {
+		_ = job.Update(job.queuedStatus(i+1, len(waiting)))
+	}
+}
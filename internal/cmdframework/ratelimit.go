@@ -0,0 +1,228 @@
+package cmdframework
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+)
+
+// ErrRateLimited is returned by the RateLimit middleware after telling the
+// user when they may try again.
+var ErrRateLimited = errors.New("rate limited")
+
+// LimitScope decides who shares a rate limit bucket.
+type LimitScope int
+
+const (
+	// ScopeSender gives every sender their own bucket
+	ScopeSender LimitScope = iota
+	// ScopeChat shares one bucket among everyone in a chat
+	ScopeChat
+	// ScopeGlobal shares one bucket among all chats of the account
+	ScopeGlobal
+)
+
+func (s LimitScope) String() string {
+	switch s {
+	case ScopeChat:
+		return "chat"
+	case ScopeGlobal:
+		return "global"
+	default:
+		return "sender"
+	}
+}
+
+// Limit declares a token bucket: Rate uses are refilled every Per, and up to
+// Burst uses (Rate if unset) can be spent at once.
+type Limit struct {
+	Rate  int
+	Per   time.Duration
+	Burst int
+	Scope LimitScope
+}
+
+func (l *Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Rate)
+}
+
+// refill is the number of tokens added per second.
+func (l *Limit) refill() float64 {
+	return float64(l.Rate) / l.Per.Seconds()
+}
+
+func (l *Limit) String() string {
+	return fmt.Sprintf("%d every %s per %s, burst %d", l.Rate, formatWait(l.Per), l.Scope, int(l.capacity()))
+}
+
+// BucketStore persists buckets so that limits survive restarts.
+type BucketStore interface {
+	LoadBucket(key string) (tokens float64, updated time.Time, ok bool, err error)
+	SaveBucket(key string, tokens float64, updated time.Time) error
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   *Limit
+}
+
+// fill returns the tokens available at now.
+func (b *bucket) fill(now time.Time) float64 {
+	elapsed := now.Sub(b.updated).Seconds()
+	return math.Min(b.limit.capacity(), b.tokens+elapsed*b.limit.refill())
+}
+
+// sweepInterval is how often full buckets, which are equivalent to missing
+// ones, are dropped from memory.
+const sweepInterval = time.Minute
+
+// RateLimiter keeps the token buckets of all rate limited commands. It is
+// safe for concurrent use.
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	store     BucketStore
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a rate limiter; store may be nil to keep buckets in
+// memory only.
+func NewRateLimiter(store BucketStore) *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*bucket),
+		store:   store,
+	}
+}
+
+// Allow takes a token from the bucket identified by key. When the bucket is
+// empty it returns false and how long until a token is available.
+func (r *RateLimiter) Allow(key string, limit *Limit) (bool, time.Duration) {
+	if limit == nil || limit.Rate <= 0 || limit.Per <= 0 {
+		return true, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+
+	b, exists := r.buckets[key]
+	if !exists {
+		b = &bucket{tokens: limit.capacity(), updated: now, limit: limit}
+		if r.store != nil {
+			tokens, updated, ok, err := r.store.LoadBucket(key)
+			if err != nil {
+				logging.Log.Error().Err(err).Str("bucket", key).Msg("Failed to load rate limit bucket")
+			} else if ok {
+				b.tokens, b.updated = tokens, updated
+			}
+		}
+		r.buckets[key] = b
+	}
+	b.limit = limit
+
+	tokens := b.fill(now)
+	if tokens < 1 {
+		wait := time.Duration((1 - tokens) / limit.refill() * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens = tokens - 1
+	b.updated = now
+	if r.store != nil {
+		if err := r.store.SaveBucket(key, b.tokens, b.updated); err != nil {
+			logging.Log.Error().Err(err).Str("bucket", key).Msg("Failed to save rate limit bucket")
+		}
+	}
+	return true, 0
+}
+
+// sweep drops buckets that have refilled completely. r.mu must be held.
+func (r *RateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < sweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, b := range r.buckets {
+		if b.fill(now) >= b.limit.capacity() {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// RateLimit enforces the RateLimit declared in each command's metadata using
+// limiter. The owner is never limited.
+func RateLimit(limiter *RateLimiter) Middleware {
+	return func(next Command) Command {
+		return &middlewareCommand{
+			next: next,
+			fn: func(ctx *Context, next Command) error {
+				meta := next.Metadata()
+				if meta.RateLimit == nil || ctx.Role >= RoleOwner {
+					return next.Execute(ctx)
+				}
+
+				allowed, wait := limiter.Allow(bucketKey(ctx, meta), meta.RateLimit)
+				if !allowed {
+					if err := ctx.Handler.SendResponse(ctx.MessageInfo, Templates.RateLimited.In(ctx.Locale, formatWait(wait))); err != nil {
+						return err
+					}
+					return ErrRateLimited
+				}
+				return next.Execute(ctx)
+			},
+		}
+	}
+}
+
+// bucketKey identifies the bucket of a command invocation, e.g.
+// "image|sender:123@s.whatsapp.net".
+func bucketKey(ctx *Context, meta *Metadata) string {
+	// Groups pass the canonical path of subcommands in ctx.Command; top-level
+	// commands may have been invoked through an alias
+	command := strings.ToLower(meta.Name)
+	if strings.Contains(ctx.Command, " ") {
+		command = strings.ToLower(ctx.Command)
+	}
+
+	switch meta.RateLimit.Scope {
+	case ScopeChat:
+		return command + "|chat:" + ctx.MessageInfo.Chat.ToNonAD().String()
+	case ScopeGlobal:
+		return command + "|global"
+	default:
+		return command + "|sender:" + ctx.MessageInfo.Sender.ToNonAD().String()
+	}
+}
+
+// formatWait renders a duration for humans, rounded up to whole seconds,
+// e.g. "45s" or "2m 5s".
+func formatWait(d time.Duration) string {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	var parts []string
+	if h := seconds / 3600; h > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", h))
+	}
+	if m := seconds % 3600 / 60; m > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", m))
+	}
+	if s := seconds % 60; s > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", s))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,161 @@
+package cmdframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+)
+
+// DefaultTimeout applies to commands that don't set Metadata.Timeout.
+const DefaultTimeout = 2 * time.Minute
+
+// ErrTimeout is returned when a command exceeds its timeout.
+var ErrTimeout = errors.New("command timed out")
+
+// PanicError is returned when a command panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// abandoned tracks the commands left running after their timeout.
+var abandoned struct {
+	wg    sync.WaitGroup
+	count atomic.Int64
+}
+
+// Abandoned returns the number of timed out commands that are still running.
+func Abandoned() int {
+	return int(abandoned.count.Load())
+}
+
+// WaitAbandoned waits until the timed out commands that are still running
+// have returned or ctx is done, e.g. on shutdown.
+func WaitAbandoned(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		abandoned.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d timed out commands still running: %w", Abandoned(), ctx.Err())
+	}
+}
+
+// ErrorReporter is notified of every command and job that fails, e.g. to
+// forward the details of panics to the owner. Failed jobs are passed as
+// JobError.
+type ErrorReporter interface {
+	ReportCommandError(ctx *Context, err error)
+}
+
+// Recover runs the command with its timeout, turns panics into PanicError and
+// answers failures with an ErrorResponse. reporter may be nil.
+//
+// A command that times out can't be stopped; it is left running with a
+// cancelled ctx.Context and its result is discarded. Commands doing slow work
+// must therefore honour ctx.Done(); until they return they are counted by
+// Abandoned and waited for by WaitAbandoned.
+func Recover(reporter ErrorReporter) Middleware {
+	return func(next Command) Command {
+		return &middlewareCommand{
+			next: next,
+			fn: func(ctx *Context, next Command) error {
+				// Recover only wraps top-level commands, so the timeout of
+				// subcommands is looked up here
+				timeout := timeoutFor(next, ctx.Args)
+
+				parent := ctx.Context
+				if parent == nil {
+					parent = context.Background()
+				}
+				runCtx, cancel := context.WithTimeout(parent, timeout)
+				defer cancel()
+
+				cmdCtx := *ctx
+				cmdCtx.Context = runCtx
+
+				done := make(chan error, 1)
+				var finished atomic.Bool
+				abandoned.wg.Add(1)
+				go func() {
+					defer abandoned.wg.Done()
+					defer func() {
+						if !finished.CompareAndSwap(false, true) {
+							abandoned.count.Add(-1)
+						}
+					}()
+					defer func() {
+						if v := recover(); v != nil {
+							done <- &PanicError{Value: v, Stack: debug.Stack()}
+						}
+					}()
+					done <- next.Execute(&cmdCtx)
+				}()
+
+				var err error
+				select {
+				case err = <-done:
+				case <-runCtx.Done():
+					if finished.CompareAndSwap(false, true) {
+						n := abandoned.count.Add(1)
+						ctx.Logger.Warn().Int64("abandoned", n).Msg("Command left running after being stopped")
+					}
+					if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+						err = fmt.Errorf("%w after %s", ErrTimeout, timeout)
+					} else {
+						err = runCtx.Err()
+					}
+				}
+				if err == nil || errors.Is(err, ErrPermissionDenied) || errors.Is(err, ErrRateLimited) {
+					// Refusals have already been answered
+					return err
+				}
+
+				if reporter != nil {
+					reporter.ReportCommandError(ctx, err)
+				}
+				_ = ctx.Handler.SendResponse(ctx.MessageInfo, ErrorResponseFor(ctx.Locale, err).String())
+				return err
+			},
+		}
+	}
+}
+
+// ErrorResponseFor maps a command error to the response shown in the chat,
+// in the language of l. The error itself is not shown, as it may hold
+// internal details; those go to the logs and, for panics, to the owner.
+func ErrorResponseFor(l *i18n.Localizer, err error) ErrorResponse {
+	var panicErr *PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		return ErrorResponse{
+			Code:    "INTERNAL",
+			Message: l.T("error.internal", "Something went wrong while running the command."),
+		}
+	case errors.Is(err, ErrTimeout):
+		return ErrorResponse{
+			Code:    "TIMEOUT",
+			Message: l.T("error.timeout", "The command took too long and was stopped."),
+		}
+	default:
+		return ErrorResponse{
+			Code:    "FAILED",
+			Message: l.T("error.failed", "The command failed. Please try again later."),
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strings"
 	"sync"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
 )
 
 type Registry struct {
@@ -157,105 +159,46 @@ func (r *Registry) GetCategories() []string {
 	return categories
 }
 
-func (r *Registry) GenerateHelp() string {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	var sb strings.Builder
-	sb.WriteString("📋 *Available Commands*\n\n")
-
-	categories := r.GetCategories()
-
-	uncategorized := make([]string, 0)
-	categorizedCommands := make(map[string]bool)
-
-	for _, cat := range categories {
-		if len(r.categories[cat]) == 0 {
-			continue
-		}
-
-		sb.WriteString(fmt.Sprintf("*%s*\n", cat))
-
-		commands := r.categories[cat]
-		sort.Strings(commands)
-
-		for _, cmdName := range commands {
-			cmd := r.commands[cmdName]
-			meta := cmd.Metadata()
-			if !meta.Hidden {
-				sb.WriteString(fmt.Sprintf("• */%s*", meta.Name))
-				if meta.Description != "" {
-					sb.WriteString(fmt.Sprintf(" - %s", meta.Description))
-				}
-				sb.WriteString("\n")
-				categorizedCommands[cmdName] = true
-			}
-		}
-		sb.WriteString("\n")
-	}
-
-	for name, cmd := range r.commands {
-		if !categorizedCommands[name] {
-			meta := cmd.Metadata()
-			if !meta.Hidden {
-				uncategorized = append(uncategorized, name)
-			}
-		}
-	}
-
-	if len(uncategorized) > 0 {
-		sb.WriteString("*Other Commands*\n")
-		sort.Strings(uncategorized)
-		for _, name := range uncategorized {
-			cmd := r.commands[name]
-			meta := cmd.Metadata()
-			sb.WriteString(fmt.Sprintf("• */%s*", meta.Name))
-			if meta.Description != "" {
-				sb.WriteString(fmt.Sprintf(" - %s", meta.Description))
-			}
-			sb.WriteString("\n")
-		}
+// GenerateCommandHelp describes a single command in the language of l.
+// cmdName may be a path such as "model set" to describe a subcommand.
+func (r *Registry) GenerateCommandHelp(l *i18n.Localizer, cmdName string) string {
+	cmd, path, exists := r.resolve(cmdName)
+	if !exists {
+		return l.T("help.not_found", "Command '%s' not found", cmdName)
 	}
 
-	return sb.String()
-}
-
-func (r *Registry) GenerateCommandHelp(cmdName string) string {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	meta := cmd.Metadata()
 
-	cmd, exists := r.Get(cmdName)
-	if !exists {
-		return fmt.Sprintf("Command '%s' not found", cmdName)
+	if children := subcommandsOf(cmd); children != nil {
+		return children.renderHelp(l, path, cmd)
 	}
 
-	meta := cmd.Metadata()
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("*Command:* */%s*\n", meta.Name))
+	sb.WriteString(l.T("help.command", "*Command:* *%s*", path) + "\n")
 
 	if len(meta.Aliases) > 0 {
 		aliases := make([]string, len(meta.Aliases))
 		for i, alias := range meta.Aliases {
 			aliases[i] = fmt.Sprintf("*/%s*", alias)
 		}
-		sb.WriteString(fmt.Sprintf("*Aliases:* %s\n", strings.Join(aliases, ", ")))
+		sb.WriteString(l.T("help.aliases", "*Aliases:* %s", strings.Join(aliases, ", ")) + "\n")
 	}
 
 	if meta.Description != "" {
-		sb.WriteString(fmt.Sprintf("*Description:* %s\n", meta.Description))
+		sb.WriteString(l.T("help.description", "*Description:* %s", meta.Description) + "\n")
 	}
 
-	if meta.Usage != "" {
-		sb.WriteString(fmt.Sprintf("*Usage:* `%s`\n", meta.Usage))
+	if usage := usageFor(path, meta); usage != "" {
+		sb.WriteString(l.T("help.usage", "*Usage:* `%s`", usage) + "\n")
 	}
 
 	if len(meta.Parameters) > 0 {
-		sb.WriteString("\n*Parameters:*\n")
+		sb.WriteString("\n" + l.T("help.parameters", "*Parameters:*") + "\n")
 		for _, param := range meta.Parameters {
 			sb.WriteString(fmt.Sprintf("• `%s`", param.Name))
 			if param.Required {
-				sb.WriteString(" *(required)*")
+				sb.WriteString(" " + l.T("help.required", "*(required)*"))
 			}
 			if param.Description != "" {
 				sb.WriteString(fmt.Sprintf(" - %s", param.Description))
@@ -264,16 +207,93 @@ func (r *Registry) GenerateCommandHelp(cmdName string) string {
 		}
 	}
 
+	if len(meta.Flags) > 0 {
+		sb.WriteString("\n" + l.T("help.flags", "*Flags:*") + "\n")
+		for _, flag := range meta.Flags {
+			sb.WriteString(fmt.Sprintf("• `%s`", flagSyntax(flag)))
+			if flag.Description != "" {
+				sb.WriteString(fmt.Sprintf(" - %s", flag.Description))
+			}
+			if flag.Default != nil && flag.Type != BoolParam {
+				sb.WriteString(" " + l.T("help.default", "(default: %v)", flag.Default))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
 	if len(meta.Examples) > 0 {
-		sb.WriteString("\n*Examples:*\n")
+		sb.WriteString("\n" + l.T("help.examples", "*Examples:*") + "\n")
 		for _, example := range meta.Examples {
 			sb.WriteString(fmt.Sprintf("• `%s`\n", example))
 		}
 	}
 
-	if meta.RequireOwner {
-		sb.WriteString("\n⚠️ *This command requires owner permissions*")
+	if meta.RateLimit != nil {
+		sb.WriteString("\n⏱️ " + l.T("help.rate_limit", "*Rate limit:* %s", meta.RateLimit))
+	}
+
+	if role := meta.RequiredRole(); role > RoleEveryone {
+		sb.WriteString("\n⚠️ " + l.T("help.requires_role", "*This command requires %s permissions*", role))
 	}
 
 	return sb.String()
 }
+
+// Usage returns the usage line of a command, generating one from its flags
+// and parameters when none is set explicitly.
+func Usage(meta *Metadata) string {
+	return usageFor("/"+meta.Name, meta)
+}
+
+func usageFor(path string, meta *Metadata) string {
+	if meta.Usage != "" {
+		return meta.Usage
+	}
+
+	parts := []string{path}
+	for _, flag := range meta.Flags {
+		syntax := "--" + flag.Name
+		if flag.Type != BoolParam {
+			syntax += "=<" + parameterTypeName(flag.Type) + ">"
+		}
+		parts = append(parts, "["+syntax+"]")
+	}
+	for _, param := range meta.Parameters {
+		name := param.Name
+		if param.Rest {
+			name += "..."
+		}
+		if param.Required {
+			parts = append(parts, "<"+name+">")
+		} else {
+			parts = append(parts, "["+name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func flagSyntax(flag Flag) string {
+	syntax := "--" + flag.Name
+	if flag.Short != "" {
+		syntax = "-" + flag.Short + ", " + syntax
+	}
+	if flag.Type != BoolParam {
+		syntax += "=<" + parameterTypeName(flag.Type) + ">"
+	}
+	return syntax
+}
+
+func parameterTypeName(typ ParameterType) string {
+	switch typ {
+	case IntParam:
+		return "int"
+	case FloatParam:
+		return "number"
+	case BoolParam:
+		return "bool"
+	case DurationParam:
+		return "duration"
+	default:
+		return "text"
+	}
+}
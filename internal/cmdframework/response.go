@@ -3,6 +3,8 @@ package cmdframework
 import (
 	"fmt"
 	"strings"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
 )
 
 type ResponseBuilder struct {
@@ -141,7 +143,7 @@ var Templates = struct {
 	},
 	RateLimited: Template{
 		name:     "RateLimited",
-		template: "⏱️ Please wait %d seconds before using this command again.",
+		template: "⏱️ Slow down! You can use this command again in %s.",
 	},
 	InternalError: Template{
 		name:     "InternalError",
@@ -156,3 +158,8 @@ var Templates = struct {
 func (t Template) Format(args ...interface{}) string {
 	return fmt.Sprintf(t.template, args...)
 }
+
+// In is Format in the language of l.
+func (t Template) In(l *i18n.Localizer, args ...interface{}) string {
+	return l.T("template."+t.name, t.template, args...)
+}
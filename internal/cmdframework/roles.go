@@ -0,0 +1,87 @@
+package cmdframework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPermissionDenied is returned by RequireRole after telling the user they
+// lack the required role.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Role is a user's permission level. Roles are ordered, so a command allowed
+// for RoleTrusted is also allowed for admins and the owner.
+type Role int
+
+const (
+	RoleBanned Role = iota - 1
+	RoleEveryone
+	RoleTrusted
+	RoleAdmin
+	RoleOwner
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleBanned:
+		return "banned"
+	case RoleEveryone:
+		return "everyone"
+	case RoleTrusted:
+		return "trusted"
+	case RoleAdmin:
+		return "admin"
+	case RoleOwner:
+		return "owner"
+	default:
+		return fmt.Sprintf("role(%d)", int(r))
+	}
+}
+
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "banned", "ban":
+		return RoleBanned, nil
+	case "everyone", "user":
+		return RoleEveryone, nil
+	case "trusted":
+		return RoleTrusted, nil
+	case "admin":
+		return RoleAdmin, nil
+	case "owner":
+		return RoleOwner, nil
+	default:
+		return RoleEveryone, fmt.Errorf("unknown role %q (expected banned, everyone, trusted, admin or owner)", s)
+	}
+}
+
+// RequiredRole is the minimum role needed to run the command. RequireOwner is
+// kept as a shorthand for MinRole: RoleOwner.
+func (m *Metadata) RequiredRole() Role {
+	if m.RequireOwner {
+		return RoleOwner
+	}
+	return m.MinRole
+}
+
+// RequireRole rejects the command with ErrPermissionDenied unless ctx.Role,
+// resolved by the dispatcher, meets the command's RequiredRole.
+func RequireRole() Middleware {
+	return func(next Command) Command {
+		return &middlewareCommand{
+			next: next,
+			fn: func(ctx *Context, next Command) error {
+				required := next.Metadata().RequiredRole()
+				if ctx.Role < required {
+					if err := ctx.Handler.SendResponse(ctx.MessageInfo,
+						Error(ctx.T("error.role_required", "This command requires %s permissions", required))); err != nil {
+						return err
+					}
+					return ErrPermissionDenied
+				}
+				return next.Execute(ctx)
+			},
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package cmdframework
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+)
+
+// minSuggestLength keeps very short names, which are close to every language
+// command, from getting suggestions.
+const minSuggestLength = 3
+
+// Suggest returns up to limit command names or aliases that are spelled
+// closest to name, best match first. Hidden commands and commands rejected by
+// accept (which may be nil) are never suggested.
+func (r *Registry) Suggest(name string, limit int, accept func(cmd Command) bool) []string {
+	name = strings.ToLower(name)
+	if len([]rune(name)) < minSuggestLength || limit <= 0 {
+		return nil
+	}
+	maxDistance := maxSuggestDistance(name)
+
+	type candidate struct {
+		spelling string
+		distance int
+	}
+	// Only the closest spelling of every command is kept
+	best := make(map[string]candidate)
+
+	r.mu.RLock()
+	consider := func(spelling, command string) {
+		distance := editDistance(name, spelling)
+		if distance > maxDistance {
+			return
+		}
+		if current, exists := best[command]; !exists || distance < current.distance {
+			best[command] = candidate{spelling: spelling, distance: distance}
+		}
+	}
+	for command := range r.commands {
+		consider(command, command)
+	}
+	for alias, command := range r.aliases {
+		consider(alias, command)
+	}
+	commands := make(map[string]Command, len(best))
+	for command := range best {
+		commands[command] = r.commands[command]
+	}
+	r.mu.RUnlock()
+
+	candidates := make([]candidate, 0, len(best))
+	for command, c := range best {
+		cmd := commands[command]
+		if cmd.Metadata().Hidden || (accept != nil && !accept(cmd)) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].spelling < candidates[j].spelling
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	result := make([]string, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.spelling
+	}
+	return result
+}
+
+// maxSuggestDistance allows more typos in longer names.
+func maxSuggestDistance(name string) int {
+	switch n := len([]rune(name)); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance is the Damerau-Levenshtein distance (optimal string alignment)
+// between a and b, so swapped letters count as a single typo.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(t)]
+}
+
+// UnknownCommand is the reply to an unknown command in the language of l,
+// listing suggestions from Suggest if there are any.
+func UnknownCommand(l *i18n.Localizer, name string, suggestions []string) string {
+	response := Templates.InvalidCommand.In(l, name)
+	if len(suggestions) == 0 {
+		return response
+	}
+
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = "`/" + s + "`"
+	}
+	return response + "\n" + l.T("suggest.did_you_mean", "Did you mean %s?", joinOr(quoted, l.T("list.or", "or")))
+}
+
+// joinOr joins items as "a", "a or b" or "a, b or c".
+func joinOr(items []string, or string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + or + " " + items[len(items)-1]
+}
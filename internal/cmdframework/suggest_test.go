@@ -0,0 +1,73 @@
+package cmdframework
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"help", "help", 0},
+		{"hepl", "help", 1},
+		{"dowload", "download", 1},
+		{"downlaod", "download", 1},
+		{"translte", "translate", 1},
+		{"kitten", "sitting", 3},
+		{"ab", "ba", 1},
+		{"abc", "ca", 3},
+		{"schedlue", "schedule", 1},
+		{"ба", "аб", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	r := NewRegistry()
+	for _, meta := range []Metadata{
+		{Name: "download", Aliases: []string{"dl"}},
+		{Name: "downloads"},
+		{Name: "translate", Aliases: []string{"tr"}},
+		{Name: "debug", Hidden: true},
+	} {
+		if err := r.Register(&SimpleCommand{Meta: meta}); err != nil {
+			t.Fatalf("Register(%s) failed: %v", meta.Name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		limit int
+		want  []string
+	}{
+		{"transposition", "dowlnoad", 3, []string{"download", "downloads"}},
+		{"limit", "dowlnoad", 1, []string{"download"}},
+		{"case insensitive", "TRANSLTE", 3, []string{"translate"}},
+		{"too short", "dx", 3, nil},
+		{"too far", "xyzzy", 3, []string{}},
+		{"hidden", "debgu", 3, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Suggest(tt.input, tt.limit, nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q, %d) = %q, want %q", tt.input, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package cmdframework
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTime is returned by ParseWhen for arguments that aren't a time.
+var ErrInvalidTime = errors.New("expected a duration like 2h30m, a time like 18:30 or a date like 2025-12-31 18:30")
+
+// WhenUsage describes the times ParseWhen accepts, for usage texts.
+const WhenUsage = "<2h30m|18:30|tomorrow 9:00|2025-12-31 18:30>"
+
+// ParseWhen reads a point in time from the leading arguments: a duration
+// from now (parsed like a DurationParam), a time of day (today, or tomorrow
+// once it has passed), "tomorrow" followed by a time of day, or a date and
+// time. Times are in the location of now. It returns the number of arguments
+// used.
+func ParseWhen(args []string, now time.Time) (time.Time, int, error) {
+	if len(args) == 0 {
+		return time.Time{}, 0, ErrInvalidTime
+	}
+
+	if value, err := parseValue(args[0], DurationParam); err == nil {
+		d := value.(time.Duration)
+		if d <= 0 {
+			return time.Time{}, 0, errors.New("the duration must be positive")
+		}
+		return now.Add(d), 1, nil
+	}
+
+	if clock, ok := parseClock(args[0]); ok {
+		at := atClock(now, clock)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, 1, nil
+	}
+
+	if strings.EqualFold(args[0], "tomorrow") && len(args) > 1 {
+		if clock, ok := parseClock(args[1]); ok {
+			return atClock(now.AddDate(0, 0, 1), clock), 2, nil
+		}
+	}
+
+	// "2025-12-31T18:30" or "2025-12-31 18:30"
+	date, clockArg, used := args[0], "", 1
+	if before, after, found := strings.Cut(args[0], "T"); found {
+		date, clockArg = before, after
+	} else if len(args) > 1 {
+		clockArg, used = args[1], 2
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, now.Location())
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidTime
+	}
+	clock, ok := parseClock(clockArg)
+	if !ok {
+		return time.Time{}, 0, ErrInvalidTime
+	}
+	at := atClock(day, clock)
+	if !at.After(now) {
+		return time.Time{}, 0, errors.New("that time has already passed")
+	}
+	return at, used, nil
+}
+
+// parseClock parses "18:30" or "9:05" into the offset from midnight.
+func parseClock(s string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// atClock returns the wall clock time clock on the day of day.
+func atClock(day time.Time, clock time.Duration) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, day.Location())
+}
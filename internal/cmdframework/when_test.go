@@ -0,0 +1,81 @@
+package cmdframework
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	now := time.Date(2025, 6, 15, 14, 0, 0, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 6, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want time.Time
+		used int
+	}{
+		{"duration", []string{"2h30m", "msg"}, now.Add(150 * time.Minute), 1},
+		{"later today", []string{"18:30", "msg"}, at(15, 18, 30), 1},
+		{"passed time of day is tomorrow", []string{"9:05"}, at(16, 9, 5), 1},
+		{"current time of day is tomorrow", []string{"14:00"}, at(16, 14, 0), 1},
+		{"tomorrow", []string{"Tomorrow", "9:00", "msg"}, at(16, 9, 0), 2},
+		{"date and time", []string{"2025-06-20", "18:30", "msg"}, at(20, 18, 30), 2},
+		{"T separated date", []string{"2025-06-20T18:30", "msg"}, at(20, 18, 30), 1},
+		{"later today by date", []string{"2025-06-15", "14:01"}, at(15, 14, 1), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used, err := ParseWhen(tt.args, now)
+			if err != nil {
+				t.Fatalf("ParseWhen(%q) failed: %v", tt.args, err)
+			}
+			if !got.Equal(tt.want) || used != tt.used {
+				t.Errorf("ParseWhen(%q) = %s, %d, want %s, %d", tt.args, got, used, tt.want, tt.used)
+			}
+			if got.Location() != loc {
+				t.Errorf("ParseWhen(%q) is in %s, want %s", tt.args, got.Location(), loc)
+			}
+		})
+	}
+}
+
+func TestParseWhenErrors(t *testing.T) {
+	now := time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		invalid bool
+	}{
+		{"no arguments", nil, true},
+		{"text", []string{"hello"}, true},
+		{"negative duration", []string{"-1h"}, false},
+		{"zero duration", []string{"0s"}, false},
+		{"tomorrow without time", []string{"tomorrow"}, true},
+		{"date without time", []string{"2025-06-20"}, true},
+		{"invalid date", []string{"2025-02-30", "10:00"}, true},
+		{"invalid time", []string{"2025-06-20", "25:00"}, true},
+		{"T date with invalid time", []string{"2025-06-20T9"}, true},
+		{"date in the past", []string{"2025-06-14", "18:00"}, false},
+		{"T date in the past", []string{"2024-12-31T23:59"}, false},
+		{"now by date", []string{"2025-06-15", "14:00"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseWhen(tt.args, now)
+			if err == nil {
+				t.Fatalf("ParseWhen(%q) succeeded, want an error", tt.args)
+			}
+			if invalid := errors.Is(err, ErrInvalidTime); invalid != tt.invalid {
+				t.Errorf("ParseWhen(%q) error = %v, want ErrInvalidTime: %v", tt.args, err, tt.invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// AuditEntry is one recorded command invocation.
+type AuditEntry struct {
+	Time     time.Time
+	Chat     string
+	Sender   string
+	Command  string
+	Args     string
+	Outcome  string
+	Error    string
+	Duration time.Duration
+}
+
+// AuditFilter narrows an audit log query; empty fields match everything.
+type AuditFilter struct {
+	Chat    string
+	Sender  string
+	Command string
+	Outcome string
+	Limit   int
+}
+
+// AuditLog gives access to the command audit trail of the account.
+type AuditLog interface {
+	AuditEntries(filter AuditFilter) ([]AuditEntry, error)
+}
+
+type AuditCommand struct {
+	audit AuditLog
+}
+
+func NewAuditCommand(audit AuditLog) *AuditCommand {
+	return &AuditCommand{audit: audit}
+}
+
+func (c *AuditCommand) Execute(ctx *framework.Context) error {
+	filter := AuditFilter{
+		Command: strings.ToLower(strings.TrimPrefix(ctx.Flags.String("command"), "/")),
+		Outcome: strings.ToLower(ctx.Flags.String("outcome")),
+		Limit:   ctx.Flags.Int("limit"),
+	}
+	if filter.Limit <= 0 || filter.Limit > 50 {
+		filter.Limit = 50
+	}
+	if ctx.Flags.Bool("here") {
+		filter.Chat = ctx.MessageInfo.Chat.String()
+	}
+	if user := ctx.Flags.String("user"); user != "" {
+		number := strings.TrimPrefix(strings.TrimPrefix(user, "@"), "+")
+		if strings.Trim(number, "0123456789") != "" {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("audit.invalid_user", "%q is not a phone number", user)))
+		}
+		filter.Sender = types.NewJID(number, types.DefaultUserServer).String()
+	}
+
+	entries, err := c.audit.AuditEntries(filter)
+	if err != nil {
+		return fmt.Errorf("failed to load the audit log: %w", err)
+	}
+	if len(entries) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Info(ctx.T("audit.none", "No matching commands in the audit log")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("audit.title", "📜 Audit Log"))
+	for _, entry := range entries {
+		line := "• " + ctx.T("audit.entry", "%s */%s* by %s - %s (%s)",
+			entry.Time.Format("Jan 2 15:04"), entry.Command, strings.SplitN(entry.Sender, "@", 2)[0],
+			entry.Outcome, entry.Duration.Round(time.Millisecond))
+		if filter.Chat == "" && entry.Chat != ctx.MessageInfo.Chat.String() {
+			line += " " + ctx.T("audit.in_chat", "in %s", entry.Chat)
+		}
+		builder.AddLine(line)
+		if entry.Args != "" {
+			builder.AddLine(fmt.Sprintf("   `%s`", entry.Args))
+		}
+		if entry.Error != "" {
+			builder.AddLine(fmt.Sprintf("   _%s_", entry.Error))
+		}
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *AuditCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "audit",
+		Description: "Show who ran which commands, newest first",
+		Category:    "Admin",
+		Usage:       "/audit [--user=<number>] [--command=<name>] [--outcome=<outcome>] [--here] [--limit=<n>]",
+		MinRole:     framework.RoleOwner,
+		Flags: []framework.Flag{
+			{Name: "user", Short: "u", Type: framework.StringParam, Description: "Only commands run by this number"},
+			{Name: "command", Short: "c", Type: framework.StringParam, Description: "Only this command"},
+			{Name: "outcome", Short: "o", Type: framework.StringParam, Description: "ok, queued, error, panic, timeout, cancelled, denied, invalid_args or rate_limited"},
+			{Name: "here", Type: framework.BoolParam, Description: "Only commands run in this chat"},
+			{Name: "limit", Short: "n", Type: framework.IntParam, Default: 10, Description: "Number of entries (max 50)"},
+		},
+		Examples: []string{
+			"/audit",
+			"/audit --here --limit=20",
+			"/audit --user=919876543210 --outcome=denied",
+		},
+	}
+}
@@ -0,0 +1,378 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ChatConfigManager persists which commands are disabled per chat, which
+// chats the bot answers in allowlist mode and where unknown commands get
+// suggestions.
+type ChatConfigManager interface {
+	DisableCommand(chat types.JID, name string) error
+	EnableCommand(chat types.JID, name string) (bool, error)
+	DisabledCommands(chat types.JID) ([]string, error)
+	SetChatAllowed(chat types.JID, allowed bool) error
+	IsChatAllowed(chat types.JID) (bool, error)
+	AllowedChats() ([]types.JID, error)
+	SetAllowlistMode(enabled bool) error
+	AllowlistMode() (bool, error)
+	SetSuggestions(chat types.JID, enabled bool) error
+	SuggestionsEnabled(chat types.JID) (bool, error)
+}
+
+type DisableCommand struct {
+	registry *framework.Registry
+	config   ChatConfigManager
+}
+
+func NewDisableCommand(registry *framework.Registry, config ChatConfigManager) *DisableCommand {
+	return &DisableCommand{registry: registry, config: config}
+}
+
+func (c *DisableCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("chatconfig.disable_usage", "Please specify a command or category to disable")))
+	}
+
+	name, ok := resolveCommandOrCategory(c.registry, ctx.Args[0])
+	if !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("chatconfig.unknown_name",
+			"%q is neither a command nor a category", strings.ToLower(strings.TrimPrefix(ctx.Args[0], "/")))))
+	}
+
+	if cmd, exists := c.registry.Get(name); exists && cmd.Metadata().Essential {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("chatconfig.essential", "/%s can't be disabled", name)))
+	}
+
+	if err := c.config.DisableCommand(ctx.MessageInfo.Chat, name); err != nil {
+		return fmt.Errorf("failed to disable %s: %w", name, err)
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("chatconfig.disabled", "*%s* disabled in this chat", name)))
+}
+
+func (c *DisableCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "disable",
+		Description: "Disable a command or category in this chat",
+		Category:    "Admin",
+		Usage:       "/disable <command|category>",
+		MinRole:     framework.RoleAdmin,
+		Essential:   true,
+		Examples: []string{
+			"/disable meme",
+			"/disable fun",
+		},
+	}
+}
+
+type EnableCommand struct {
+	registry *framework.Registry
+	config   ChatConfigManager
+}
+
+func NewEnableCommand(registry *framework.Registry, config ChatConfigManager) *EnableCommand {
+	return &EnableCommand{registry: registry, config: config}
+}
+
+func (c *EnableCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("chatconfig.enable_usage", "Please specify a command or category to enable")))
+	}
+
+	// Names that no longer resolve (e.g. a removed command) can still be
+	// cleaned up, so fall back to the name as given.
+	name, ok := resolveCommandOrCategory(c.registry, ctx.Args[0])
+	if !ok {
+		name = strings.ToLower(strings.TrimPrefix(ctx.Args[0], "/"))
+	}
+
+	enabled, err := c.config.EnableCommand(ctx.MessageInfo.Chat, name)
+	if err != nil {
+		return fmt.Errorf("failed to enable %s: %w", name, err)
+	}
+	if !enabled {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("chatconfig.not_disabled", "*%s* is not disabled in this chat", name)))
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("chatconfig.enabled", "*%s* enabled in this chat", name)))
+}
+
+func (c *EnableCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "enable",
+		Description: "Re-enable a command or category in this chat",
+		Category:    "Admin",
+		Usage:       "/enable <command|category>",
+		MinRole:     framework.RoleAdmin,
+		Essential:   true,
+		Examples: []string{
+			"/enable meme",
+			"/enable fun",
+		},
+	}
+}
+
+// NewChatConfigCommand builds /chatconfig, which shows the chat's settings,
+// manages the allowlist and toggles command suggestions.
+func NewChatConfigCommand(config ChatConfigManager) (*framework.Group, error) {
+	group, err := framework.NewGroup(framework.Metadata{
+		Name:        "chatconfig",
+		Description: "Show this chat's settings and manage the chat allowlist",
+		Category:    "Admin",
+		MinRole:     framework.RoleAdmin,
+		Essential:   true,
+		Examples: []string{
+			"/chatconfig",
+			"/chatconfig allow",
+			"/chatconfig allowlist on",
+			"/chatconfig suggest off",
+		},
+	},
+		&chatConfigShowCommand{config: config},
+		&chatConfigAllowCommand{config: config, allow: true},
+		&chatConfigAllowCommand{config: config, allow: false},
+		&chatConfigModeCommand{config: config},
+		&chatConfigSuggestCommand{config: config},
+	)
+	if err != nil {
+		return nil, err
+	}
+	group.Default = "show"
+	return group, nil
+}
+
+type chatConfigShowCommand struct {
+	config ChatConfigManager
+}
+
+func (c *chatConfigShowCommand) Execute(ctx *framework.Context) error {
+	chat := ctx.MessageInfo.Chat
+
+	disabled, err := c.config.DisabledCommands(chat)
+	if err != nil {
+		return err
+	}
+	mode, err := c.config.AllowlistMode()
+	if err != nil {
+		return err
+	}
+	allowed, err := c.config.IsChatAllowed(chat)
+	if err != nil {
+		return err
+	}
+	suggestions, err := c.config.SuggestionsEnabled(chat)
+	if err != nil {
+		return err
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("chatconfig.title", "⚙️ Chat Configuration"))
+	builder.AddLine(ctx.T("chatconfig.chat", "*Chat:* %s", chat.ToNonAD()))
+	if len(disabled) == 0 {
+		builder.AddLine(ctx.T("chatconfig.disabled_none", "*Disabled:* none"))
+	} else {
+		builder.AddLine(ctx.T("chatconfig.disabled_list", "*Disabled:* %s", strings.Join(disabled, ", ")))
+	}
+	builder.AddLine(ctx.T("chatconfig.mode", "*Allowlist mode:* %s", onOff(ctx, mode)))
+	builder.AddLine(ctx.T("chatconfig.allowed", "*Allowlisted:* %s", yesNo(ctx, allowed)))
+	builder.AddLine(ctx.T("chatconfig.suggestions", "*Command suggestions:* %s", onOff(ctx, suggestions)))
+
+	// The full allowlist spans other chats, so only the owner gets to see it
+	if mode && ctx.Role >= framework.RoleOwner {
+		chats, err := c.config.AllowedChats()
+		if err == nil && len(chats) > 0 {
+			builder.AddEmptyLine().AddBold(ctx.T("chatconfig.allowed_chats", "Allowlisted chats"))
+			for _, jid := range chats {
+				builder.AddLine(fmt.Sprintf("• %s", jid))
+			}
+		}
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *chatConfigShowCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "show",
+		Description: "Show disabled commands and allowlist status",
+		MinRole:     framework.RoleAdmin,
+		Essential:   true,
+	}
+}
+
+type chatConfigAllowCommand struct {
+	config ChatConfigManager
+	allow  bool
+}
+
+func (c *chatConfigAllowCommand) Execute(ctx *framework.Context) error {
+	chat := ctx.MessageInfo.Chat
+	if len(ctx.Args) > 0 {
+		jid, err := types.ParseJID(ctx.Args[0])
+		if err != nil || jid.User == "" {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("chatconfig.invalid_jid", "Invalid chat JID %q", ctx.Args[0])))
+		}
+		chat = jid
+	}
+
+	if err := c.config.SetChatAllowed(chat, c.allow); err != nil {
+		return err
+	}
+
+	if c.allow {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(ctx.T("chatconfig.allowed_chat", "%s added to the allowlist", chat.ToNonAD())))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("chatconfig.denied_chat", "%s removed from the allowlist", chat.ToNonAD())))
+}
+
+func (c *chatConfigAllowCommand) Metadata() *framework.Metadata {
+	if c.allow {
+		return &framework.Metadata{
+			Name:        "allow",
+			Description: "Add this (or the given) chat to the allowlist",
+			Usage:       "/chatconfig allow [chat-jid]",
+			MinRole:     framework.RoleOwner,
+			Essential:   true,
+		}
+	}
+	return &framework.Metadata{
+		Name:        "deny",
+		Description: "Remove this (or the given) chat from the allowlist",
+		Usage:       "/chatconfig deny [chat-jid]",
+		MinRole:     framework.RoleOwner,
+		Essential:   true,
+	}
+}
+
+type chatConfigModeCommand struct {
+	config ChatConfigManager
+}
+
+func (c *chatConfigModeCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		mode, err := c.config.AllowlistMode()
+		if err != nil {
+			return err
+		}
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("chatconfig.mode_is", "Allowlist mode is %s", onOff(ctx, mode))))
+	}
+
+	var enabled bool
+	switch strings.ToLower(ctx.Args[0]) {
+	case "on", "true", "enable":
+		enabled = true
+	case "off", "false", "disable":
+		enabled = false
+	default:
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("usage.line", "Usage: `%s`", "/chatconfig allowlist on|off")))
+	}
+
+	if err := c.config.SetAllowlistMode(enabled); err != nil {
+		return err
+	}
+
+	message := ctx.T("chatconfig.mode_set", "Allowlist mode %s", onOff(ctx, enabled))
+	if enabled {
+		message += "\n" + ctx.T("chatconfig.mode_on_hint", "The bot now only answers in allowlisted chats; use /chatconfig allow in a chat to add it")
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(message))
+}
+
+func (c *chatConfigModeCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "allowlist",
+		Description: "Only answer in allowlisted chats (on|off)",
+		Usage:       "/chatconfig allowlist [on|off]",
+		MinRole:     framework.RoleOwner,
+		Essential:   true,
+	}
+}
+
+type chatConfigSuggestCommand struct {
+	config ChatConfigManager
+}
+
+func (c *chatConfigSuggestCommand) Execute(ctx *framework.Context) error {
+	chat := ctx.MessageInfo.Chat
+	if len(ctx.Args) == 0 {
+		enabled, err := c.config.SuggestionsEnabled(chat)
+		if err != nil {
+			return err
+		}
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("chatconfig.suggest_is", "Command suggestions are %s in this chat", onOff(ctx, enabled))))
+	}
+
+	var enabled bool
+	switch strings.ToLower(ctx.Args[0]) {
+	case "on", "true", "enable":
+		enabled = true
+	case "off", "false", "disable":
+		enabled = false
+	default:
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("usage.line", "Usage: `%s`", "/chatconfig suggest on|off")))
+	}
+
+	if err := c.config.SetSuggestions(chat, enabled); err != nil {
+		return err
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("chatconfig.suggest_set", "Command suggestions %s in this chat", onOff(ctx, enabled))))
+}
+
+func (c *chatConfigSuggestCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "suggest",
+		Description: "Answer unknown commands with \"did you mean\" suggestions (on|off)",
+		Usage:       "/chatconfig suggest [on|off]",
+		MinRole:     framework.RoleAdmin,
+		Essential:   true,
+	}
+}
+
+// resolveCommandOrCategory maps a command (or alias) to its canonical name,
+// or returns the lowercase category name. It reports false if name is
+// neither.
+func resolveCommandOrCategory(registry *framework.Registry, name string) (string, bool) {
+	name = strings.ToLower(strings.TrimPrefix(name, "/"))
+
+	if cmd, exists := registry.Get(name); exists {
+		return strings.ToLower(cmd.Metadata().Name), true
+	}
+	for _, category := range registry.GetCategories() {
+		if strings.ToLower(category) == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func onOff(ctx *framework.Context, b bool) string {
+	if b {
+		return ctx.T("chatconfig.on", "on")
+	}
+	return ctx.T("chatconfig.off", "off")
+}
+
+func yesNo(ctx *framework.Context, b bool) string {
+	if b {
+		return ctx.T("chatconfig.yes", "yes")
+	}
+	return ctx.T("chatconfig.no", "no")
+}
@@ -4,41 +4,91 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 )
 
-type SetModelCommand struct{}
+// NewModelCommand builds /model with its set, get and list subcommands.
+func NewModelCommand() (*framework.Group, error) {
+	group, err := framework.NewGroup(framework.Metadata{
+		Name:        "model",
+		Description: "Show or change the translation AI model",
+		Category:    "Admin",
+		Examples: []string{
+			"/model",
+			"/model list",
+			"/model set gemini-2.0-flash",
+		},
+	}, &SetModelCommand{}, &GetModelCommand{}, &ListModelsCommand{})
+	if err != nil {
+		return nil, err
+	}
+	group.Default = "get"
+	return group, nil
+}
+
+// NewModelAliases returns the hidden commands that keep the old flat names
+// working: /setmodel, /getmodel, /settemp and /gettemp run /model set,
+// /model get, /temp set and /temp get.
+func NewModelAliases() []framework.Command {
+	return []framework.Command{
+		&aliasCommand{name: "setmodel", path: "model set", target: &SetModelCommand{}},
+		&aliasCommand{name: "getmodel", path: "model get", target: &GetModelCommand{}},
+		&aliasCommand{name: "settemp", path: "temp set", target: &SetTempCommand{}},
+		&aliasCommand{name: "gettemp", path: "temp get", target: &GetTempCommand{}},
+	}
+}
+
+// aliasCommand runs a subcommand under an old top-level name. It takes the
+// subcommand's metadata, so permissions and parameters are checked the same.
+type aliasCommand struct {
+	name   string
+	path   string
+	target framework.Command
+}
+
+func (c *aliasCommand) Execute(ctx *framework.Context) error {
+	return c.target.Execute(ctx)
+}
 
-func NewSetModelCommand() *SetModelCommand {
-	return &SetModelCommand{}
+func (c *aliasCommand) Metadata() *framework.Metadata {
+	meta := *c.target.Metadata()
+	meta.Name = c.name
+	meta.Aliases = nil
+	meta.Description = fmt.Sprintf("Same as /%s", c.path)
+	meta.Category = "Admin"
+	meta.Hidden = true
+	meta.Examples = nil
+	return &meta
 }
 
+type SetModelCommand struct{}
+
 func (c *SetModelCommand) Execute(ctx *framework.Context) error {
 	if len(ctx.Args) == 0 {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error("Please specify a model ID"))
+			framework.Error(ctx.T("model.usage", "Please specify a model ID")))
 	}
 
 	modelID := ctx.Args[0]
 	if err := ctx.Handler.GetTranslator().SetModel(modelID); err != nil {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error(fmt.Sprintf("Failed to set model: %v", err)))
+			framework.Error(ctx.T("model.set_failed", "Failed to set model: %v", err)))
 	}
 
 	return ctx.Handler.SendResponse(ctx.MessageInfo,
-		framework.Success(fmt.Sprintf("Translation model set to: %s", modelID)))
+		framework.Success(ctx.T("model.set", "Translation model set to: %s", modelID)))
 }
 
 func (c *SetModelCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "setmodel",
+		Name:         "set",
 		Description:  "Set the translation AI model",
 		Category:     "Admin",
-		Usage:        "/setmodel <model-id>",
 		RequireOwner: true,
 		Examples: []string{
-			"/setmodel gemini-1.5-flash",
-			"/setmodel gemini-2.0-flash",
+			"/model set gemini-1.5-flash",
+			"/model set gemini-2.0-flash",
 		},
 		Parameters: []framework.Parameter{
 			{
@@ -53,63 +103,104 @@ func (c *SetModelCommand) Metadata() *framework.Metadata {
 
 type GetModelCommand struct{}
 
-func NewGetModelCommand() *GetModelCommand {
-	return &GetModelCommand{}
-}
-
 func (c *GetModelCommand) Execute(ctx *framework.Context) error {
 	model := ctx.Handler.GetTranslator().GetModel()
 	return ctx.Handler.SendResponse(ctx.MessageInfo,
-		framework.Info(fmt.Sprintf("Current translation model: %s", model)))
+		framework.Info(ctx.T("model.current", "Current translation model: %s", model)))
 }
 
 func (c *GetModelCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "getmodel",
-		Description:  "Get current translation model",
-		Category:     "Admin",
-		Usage:        "/getmodel",
-		RequireOwner: false,
+		Name:        "get",
+		Aliases:     []string{"show"},
+		Description: "Get current translation model",
+		Category:    "Admin",
 	}
 }
 
-type SetTempCommand struct{}
+type ListModelsCommand struct{}
 
-func NewSetTempCommand() *SetTempCommand {
-	return &SetTempCommand{}
+func (c *ListModelsCommand) Execute(ctx *framework.Context) error {
+	current := ctx.Handler.GetTranslator().GetModel()
+	if len(config.AppConfig.TranslationModels) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("model.current", "Current translation model: %s", current)+"\n"+
+				ctx.T("model.none_configured", "Set TRANSLATION_MODELS to list the models to choose from.")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("model.list_title", "🤖 Translation Models"))
+	for _, model := range config.AppConfig.TranslationModels {
+		if model == current {
+			builder.AddLine(fmt.Sprintf("• `%s` ✅", model))
+		} else {
+			builder.AddLine(fmt.Sprintf("• `%s`", model))
+		}
+	}
+	builder.AddEmptyLine().AddItalic(ctx.T("model.list_hint", "Use /model set <model> to switch"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *ListModelsCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "list",
+		Aliases:     []string{"ls"},
+		Description: "List the configured translation models",
+		Category:    "Admin",
+	}
 }
 
+// NewTempCommand builds /temp with its set and get subcommands.
+func NewTempCommand() (*framework.Group, error) {
+	group, err := framework.NewGroup(framework.Metadata{
+		Name:        "temp",
+		Aliases:     []string{"temperature"},
+		Description: "Show or change the AI temperature",
+		Category:    "Admin",
+		Examples: []string{
+			"/temp",
+			"/temp set 0.7",
+		},
+	}, &SetTempCommand{}, &GetTempCommand{})
+	if err != nil {
+		return nil, err
+	}
+	group.Default = "get"
+	return group, nil
+}
+
+type SetTempCommand struct{}
+
 func (c *SetTempCommand) Execute(ctx *framework.Context) error {
 	if len(ctx.Args) == 0 {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error("Please specify a temperature value between 0.0 and 1.0"))
+			framework.Error(ctx.T("temp.usage", "Please specify a temperature value between 0.0 and 1.0")))
 	}
 
 	temp, err := strconv.ParseFloat(ctx.Args[0], 64)
 	if err != nil {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error("Invalid temperature value. Please provide a number between 0.0 and 1.0"))
+			framework.Error(ctx.T("temp.invalid", "Invalid temperature value. Please provide a number between 0.0 and 1.0")))
 	}
 
 	if err := ctx.Handler.GetTranslator().SetTemperature(temp); err != nil {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error(fmt.Sprintf("Failed to set temperature: %v", err)))
+			framework.Error(ctx.T("temp.set_failed", "Failed to set temperature: %v", err)))
 	}
 
 	return ctx.Handler.SendResponse(ctx.MessageInfo,
-		framework.Success(fmt.Sprintf("Temperature set to: %.1f", temp)))
+		framework.Success(ctx.T("temp.set", "Temperature set to: %.1f", temp)))
 }
 
 func (c *SetTempCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "settemp",
+		Name:         "set",
 		Description:  "Set AI temperature (0.0-1.0)",
 		Category:     "Admin",
-		Usage:        "/settemp <temperature>",
 		RequireOwner: true,
 		Examples: []string{
-			"/settemp 0.7",
-			"/settemp 0.3",
+			"/temp set 0.7",
+			"/temp set 0.3",
 		},
 		Parameters: []framework.Parameter{
 			{
@@ -134,22 +225,17 @@ func (c *SetTempCommand) Metadata() *framework.Metadata {
 
 type GetTempCommand struct{}
 
-func NewGetTempCommand() *GetTempCommand {
-	return &GetTempCommand{}
-}
-
 func (c *GetTempCommand) Execute(ctx *framework.Context) error {
 	temp := ctx.Handler.GetTranslator().GetTemperature()
 	return ctx.Handler.SendResponse(ctx.MessageInfo,
-		framework.Info(fmt.Sprintf("Current temperature: %.1f", temp)))
+		framework.Info(ctx.T("temp.current", "Current temperature: %.1f", temp)))
 }
 
 func (c *GetTempCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "gettemp",
-		Description:  "Get current AI temperature",
-		Category:     "Admin",
-		Usage:        "/gettemp",
-		RequireOwner: false,
+		Name:        "get",
+		Aliases:     []string{"show"},
+		Description: "Get current AI temperature",
+		Category:    "Admin",
 	}
 }
@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// RoleManager persists role assignments for the account.
+type RoleManager interface {
+	SetRole(jid types.JID, role framework.Role, grantedBy types.JID) error
+	RemoveRole(jid types.JID) (bool, error)
+	Roles() (map[string]framework.Role, error)
+}
+
+type GrantCommand struct {
+	roles RoleManager
+}
+
+func NewGrantCommand(roles RoleManager) *GrantCommand {
+	return &GrantCommand{roles: roles}
+}
+
+func (c *GrantCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("roles.grant_usage", "Usage: /grant <@user|number> <role> (or reply to a message with /grant <role>)")))
+	}
+
+	role, err := framework.ParseRole(ctx.Args[len(ctx.Args)-1])
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("roles.unknown",
+			"Unknown role %q (expected banned, everyone, trusted, admin or owner)", ctx.Args[len(ctx.Args)-1])))
+	}
+
+	target, err := targetUser(ctx, ctx.Args[:len(ctx.Args)-1])
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error()))
+	}
+
+	if err := c.roles.SetRole(target, role, ctx.MessageInfo.Sender); err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("roles.granted", "%s is now *%s*", target.User, role)))
+}
+
+func (c *GrantCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "grant",
+		Description: "Assign a role (banned, everyone, trusted, admin, owner) to a user",
+		Category:    "Admin",
+		Usage:       "/grant <@user|number> <role>",
+		MinRole:     framework.RoleOwner,
+		Examples: []string{
+			"/grant @friend trusted",
+			"/grant 919812345678 admin",
+			"Reply to a message with /grant banned",
+		},
+	}
+}
+
+type RevokeCommand struct {
+	roles RoleManager
+}
+
+func NewRevokeCommand(roles RoleManager) *RevokeCommand {
+	return &RevokeCommand{roles: roles}
+}
+
+func (c *RevokeCommand) Execute(ctx *framework.Context) error {
+	target, err := targetUser(ctx, ctx.Args)
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error()))
+	}
+
+	removed, err := c.roles.RemoveRole(target)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	if !removed {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("roles.none_assigned", "%s has no assigned role", target.User)))
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("roles.revoked", "Removed the role of %s", target.User)))
+}
+
+func (c *RevokeCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "revoke",
+		Description: "Remove a user's assigned role",
+		Category:    "Admin",
+		Usage:       "/revoke <@user|number>",
+		MinRole:     framework.RoleOwner,
+		Examples: []string{
+			"/revoke @friend",
+			"Reply to a message with /revoke",
+		},
+	}
+}
+
+type RolesCommand struct {
+	roles RoleManager
+}
+
+func NewRolesCommand(roles RoleManager) *RolesCommand {
+	return &RolesCommand{roles: roles}
+}
+
+func (c *RolesCommand) Execute(ctx *framework.Context) error {
+	roles, err := c.roles.Roles()
+	if err != nil {
+		return fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	if len(roles) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("roles.none", "No roles assigned. Group admins are treated as admins in their groups.")))
+	}
+
+	jids := make([]string, 0, len(roles))
+	for jid := range roles {
+		jids = append(jids, jid)
+	}
+	sort.Slice(jids, func(i, j int) bool {
+		if roles[jids[i]] != roles[jids[j]] {
+			return roles[jids[i]] > roles[jids[j]]
+		}
+		return jids[i] < jids[j]
+	})
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("roles.title", "👥 Roles"))
+	for _, jid := range jids {
+		builder.AddLine(fmt.Sprintf("• %s - *%s*", strings.SplitN(jid, "@", 2)[0], roles[jid]))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *RolesCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "roles",
+		Description: "List assigned roles",
+		Category:    "Admin",
+		Usage:       "/roles",
+		MinRole:     framework.RoleOwner,
+	}
+}
+
+// targetUser picks the user a role command is about: a mention, the author of
+// the quoted message, or a phone number given as argument.
+func targetUser(ctx *framework.Context, args []string) (types.JID, error) {
+	contextInfo := ctx.Message.GetExtendedTextMessage().GetContextInfo()
+
+	if mentioned := contextInfo.GetMentionedJID(); len(mentioned) > 0 {
+		return types.ParseJID(mentioned[0])
+	}
+
+	if len(args) > 0 {
+		number := strings.TrimPrefix(strings.TrimPrefix(args[0], "@"), "+")
+		if strings.Contains(number, "@") {
+			return types.ParseJID(number)
+		}
+		if strings.Trim(number, "0123456789") != "" {
+			return types.EmptyJID, errors.New(ctx.T("roles.invalid_number", "%q is not a phone number", args[0]))
+		}
+		return types.NewJID(number, types.DefaultUserServer), nil
+	}
+
+	if participant := contextInfo.GetParticipant(); participant != "" {
+		return types.ParseJID(participant)
+	}
+	if contextInfo.GetStanzaID() != "" && !ctx.MessageInfo.IsGroup {
+		return ctx.MessageInfo.Chat, nil
+	}
+
+	return types.EmptyJID, errors.New(ctx.T("roles.no_target", "Mention a user, give their number or reply to one of their messages"))
+}
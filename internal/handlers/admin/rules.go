@@ -0,0 +1,389 @@
+package admin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxRules bounds the rules of an account, which are checked against every
+// incoming message.
+const maxRules = 100
+
+// Chats of an AutoReplyRule besides chat JIDs.
+const (
+	RuleChatDirect = "dm"
+	RuleChatAny    = "*"
+)
+
+// AutoReplyRule answers messages in Chat matching Pattern with Reply. A
+// keyword pattern matches whole words, a regex pattern is case-insensitive.
+// From and To ("15:04", both empty for all day) limit the rule to a time of
+// day in the timezone of its creator.
+type AutoReplyRule struct {
+	ID        int64
+	Chat      string
+	Pattern   string
+	Regex     bool
+	Reply     string
+	Translate bool
+	Cooldown  time.Duration
+	From      string
+	To        string
+}
+
+// RuleMatch is a rule matching a test message. Active reports whether the
+// rule is within its time window and CoolingDown whether it answered in the
+// chat within its cooldown; Reply is what it would send.
+type RuleMatch struct {
+	Rule        AutoReplyRule
+	Active      bool
+	CoolingDown bool
+	Reply       string
+}
+
+// RuleManager persists auto-reply rules and evaluates them.
+type RuleManager interface {
+	AddRule(msgInfo types.MessageInfo, rule AutoReplyRule) (int64, error)
+	Rules() ([]AutoReplyRule, error)
+	RemoveRule(id int64) (bool, error)
+	// TestRules returns the rules text would match if it was sent to the
+	// chat of msgInfo by its sender
+	TestRules(msgInfo types.MessageInfo, text string) ([]RuleMatch, error)
+}
+
+// NewRuleCommand builds /rule, which manages the owner's auto-reply rules.
+func NewRuleCommand(rules RuleManager) (*framework.Group, error) {
+	group, err := framework.NewGroup(framework.Metadata{
+		Name:         "rule",
+		Aliases:      []string{"rules"},
+		Description:  "Reply automatically to messages matching a keyword or pattern",
+		Category:     "Admin",
+		RequireOwner: true,
+		Examples: []string{
+			"/rule add price The price list is at example.com/prices",
+			"/rule add --chat=dm --translate \"opening hours\" We're open 9:00-18:00, Monday to Friday",
+			"/rule add --regex --between=22:00-07:00 \"^(hi|hello)\\b\" I'm asleep, I'll answer in the morning",
+			"/rule list",
+			"/rule test what's the price?",
+			"/rule remove 3",
+		},
+	},
+		&ruleAddCommand{rules: rules},
+		&ruleListCommand{rules: rules},
+		&ruleRemoveCommand{rules: rules},
+		&ruleTestCommand{rules: rules},
+	)
+	if err != nil {
+		return nil, err
+	}
+	group.Default = "list"
+	return group, nil
+}
+
+type ruleAddCommand struct {
+	rules RuleManager
+}
+
+func (c *ruleAddCommand) Execute(ctx *framework.Context) error {
+	// The pattern and reply are taken as typed: shell escapes would eat the
+	// backslashes of a regex and the apostrophes of a reply
+	pattern, reply := cutPattern(ctx.RawRest(0))
+	if pattern == "" || reply == "" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("rule.add_usage",
+			"Please give a keyword or pattern and the reply, e.g. `/rule add price See example.com/prices`")))
+	}
+
+	rule := AutoReplyRule{
+		Pattern:   pattern,
+		Regex:     ctx.Flags.Bool("regex"),
+		Reply:     reply,
+		Translate: ctx.Flags.Bool("translate"),
+		Cooldown:  ctx.Flags.Duration("cooldown"),
+	}
+	if rule.Cooldown < 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("rule.negative_cooldown", "The cooldown can't be negative")))
+	}
+	if rule.Regex {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("rule.invalid_regex", "Invalid regular expression: %v", err)))
+		}
+	}
+
+	chat, ok := ruleChat(ctx.Flags.String("chat"), ctx.MessageInfo.Chat)
+	if !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("rule.invalid_chat",
+			"--chat must be here, dm, all or a chat JID, not %q", ctx.Flags.String("chat"))))
+	}
+	rule.Chat = chat
+
+	if between := ctx.Flags.String("between"); between != "" {
+		var ok bool
+		if rule.From, rule.To, ok = parseWindow(between); !ok {
+			return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("rule.invalid_window",
+				"--between needs two different times, e.g. 09:00-18:00")))
+		}
+	}
+
+	existing, err := c.rules.Rules()
+	if err != nil {
+		return err
+	}
+	if len(existing) >= maxRules {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("rule.too_many", "There are already %d rules, remove some first", len(existing))))
+	}
+
+	id, err := c.rules.AddRule(ctx.MessageInfo, rule)
+	if err != nil {
+		return fmt.Errorf("failed to save rule: %w", err)
+	}
+	rule.ID = id
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("rule.added", "Rule #%d added: %s", id, describeRule(ctx, rule))))
+}
+
+func (c *ruleAddCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:         "add",
+		Description:  "Add a rule; quote patterns with spaces",
+		Category:     "Admin",
+		Usage:        "/rule add [--chat=here|dm|all|<jid>] [--regex] [--translate] [--cooldown=<duration>] [--between=<HH:MM-HH:MM>] <pattern> <reply>",
+		RequireOwner: true,
+		Flags: []framework.Flag{
+			{Name: "chat", Short: "c", Type: framework.StringParam, Default: "here", Description: "Where the rule applies: this chat, every direct chat, every chat or a chat JID"},
+			{Name: "regex", Short: "r", Type: framework.BoolParam, Description: "The pattern is a regular expression"},
+			{Name: "translate", Short: "t", Type: framework.BoolParam, Description: "Translate the reply to the sender's language"},
+			{Name: "cooldown", Type: framework.DurationParam, Default: time.Minute, Description: "Answer at most once per chat in this time"},
+			{Name: "between", Short: "b", Type: framework.StringParam, Description: "Only answer between these times of day"},
+		},
+		Examples: []string{
+			"/rule add price The price list is at example.com/prices",
+			"/rule add --chat=dm --cooldown=1h \"opening hours\" We're open 9:00-18:00",
+		},
+	}
+}
+
+// cutPattern splits the pattern of /rule add from its reply. A pattern
+// with spaces is quoted; the quotes are removed but nothing inside them is
+// unescaped, so regular expressions keep their backslashes.
+func cutPattern(raw string) (pattern, reply string) {
+	raw = strings.TrimSpace(raw)
+	open, size := utf8.DecodeRuneInString(raw)
+	closing := map[rune]string{'"': `"”`, '“': `"”`, '”': `"”`, '\'': `'’`, '‘': `'’`}[open]
+	if closing != "" {
+		if end := strings.IndexAny(raw[size:], closing); end >= 0 {
+			_, closeSize := utf8.DecodeRuneInString(raw[size+end:])
+			return raw[size : size+end], strings.TrimSpace(raw[size+end+closeSize:])
+		}
+	}
+
+	end := strings.IndexFunc(raw, unicode.IsSpace)
+	if end < 0 {
+		return raw, ""
+	}
+	return raw[:end], strings.TrimSpace(raw[end:])
+}
+
+type ruleListCommand struct {
+	rules RuleManager
+}
+
+func (c *ruleListCommand) Execute(ctx *framework.Context) error {
+	rules, err := c.rules.Rules()
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("rule.none", "No rules yet, add one with `/rule add <pattern> <reply>`")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("rule.list_title", "🤖 Auto-Reply Rules"))
+	for _, rule := range rules {
+		builder.AddLine(fmt.Sprintf("*#%d* %s", rule.ID, describeRule(ctx, rule)))
+		builder.AddLine("   _" + shorten(rule.Reply, 60) + "_")
+	}
+	builder.AddEmptyLine().AddItalic(ctx.T("rule.list_hint", "Use /rule remove <id> to remove one"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *ruleListCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:         "list",
+		Aliases:      []string{"ls"},
+		Description:  "List the rules",
+		Category:     "Admin",
+		RequireOwner: true,
+	}
+}
+
+type ruleRemoveCommand struct {
+	rules RuleManager
+}
+
+func (c *ruleRemoveCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("rule.remove_usage", "Please give the ID of the rule")))
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(ctx.Args[0], "#"), 10, 64)
+	existed := false
+	if err == nil {
+		if existed, err = c.rules.RemoveRule(id); err != nil {
+			return fmt.Errorf("failed to remove rule: %w", err)
+		}
+	}
+	if !existed {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("rule.unknown", "No rule #%s", strings.TrimPrefix(ctx.Args[0], "#"))))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(ctx.T("rule.removed", "Rule #%d removed", id)))
+}
+
+func (c *ruleRemoveCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:         "remove",
+		Aliases:      []string{"rm", "delete"},
+		Description:  "Remove a rule",
+		Category:     "Admin",
+		Usage:        "/rule remove <id>",
+		RequireOwner: true,
+	}
+}
+
+type ruleTestCommand struct {
+	rules RuleManager
+}
+
+func (c *ruleTestCommand) Execute(ctx *framework.Context) error {
+	text := strings.TrimSpace(ctx.RawArgs)
+	if text == "" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(
+			ctx.T("rule.test_usage", "Please give a message to test, e.g. `/rule test what's the price?`")))
+	}
+
+	matches, err := c.rules.TestRules(ctx.MessageInfo, text)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("rule.test_none", "No rule matches that message in this chat")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("rule.test_title", "🧪 Matching Rules"))
+	answered := false
+	for _, match := range matches {
+		status := ctx.T("rule.test_replies", "would reply")
+		switch {
+		case !match.Active:
+			status = ctx.T("rule.test_inactive", "outside its time window")
+		case match.CoolingDown:
+			status = ctx.T("rule.test_cooling", "cooling down")
+		case answered:
+			status = ctx.T("rule.test_shadowed", "shadowed by an earlier rule")
+		default:
+			answered = true
+		}
+		builder.AddLine(fmt.Sprintf("*#%d* %s - %s", match.Rule.ID, describeRule(ctx, match.Rule), status))
+		builder.AddLine("   _" + shorten(match.Reply, 200) + "_")
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *ruleTestCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:         "test",
+		Description:  "Show which rules a message would trigger in this chat",
+		Category:     "Admin",
+		Usage:        "/rule test <message>",
+		RequireOwner: true,
+	}
+}
+
+// ruleChat resolves the --chat flag of /rule add.
+func ruleChat(value string, current types.JID) (string, bool) {
+	switch strings.ToLower(value) {
+	case "", "here":
+		return current.ToNonAD().String(), true
+	case "dm", "dms":
+		return RuleChatDirect, true
+	case "all", "any", "*":
+		return RuleChatAny, true
+	}
+
+	jid, err := types.ParseJID(value)
+	if err != nil || !strings.Contains(value, "@") {
+		return "", false
+	}
+	return jid.ToNonAD().String(), true
+}
+
+// parseWindow parses a time of day window such as 09:00-18:00 or, across
+// midnight, 22:00-07:00.
+func parseWindow(value string) (string, string, bool) {
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		return "", "", false
+	}
+
+	var clocks [2]string
+	for i, s := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return "", "", false
+		}
+		clocks[i] = t.Format("15:04")
+	}
+	return clocks[0], clocks[1], clocks[0] != clocks[1]
+}
+
+// describeRule summarizes a rule on one line.
+func describeRule(ctx *framework.Context, rule AutoReplyRule) string {
+	pattern := fmt.Sprintf("`%s`", rule.Pattern)
+	if rule.Regex {
+		pattern = fmt.Sprintf("`/%s/`", rule.Pattern)
+	}
+
+	where := rule.Chat
+	switch rule.Chat {
+	case RuleChatDirect:
+		where = ctx.T("rule.direct_chats", "direct chats")
+	case RuleChatAny:
+		where = ctx.T("rule.all_chats", "all chats")
+	}
+
+	parts := []string{ctx.T("rule.pattern_in", "%s in %s", pattern, where)}
+	if rule.Translate {
+		parts = append(parts, "🌐 "+ctx.T("rule.translated", "translated"))
+	}
+	if rule.Cooldown > 0 {
+		parts = append(parts, "⏱ "+rule.Cooldown.String())
+	}
+	if rule.From != "" {
+		parts = append(parts, "🕘 "+rule.From+"-"+rule.To)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// shorten cuts text to at most n runes for listings.
+func shorten(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return text
+}
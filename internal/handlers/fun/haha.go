@@ -1,15 +1,13 @@
 package fun
 
 import (
+	"errors"
 	"fmt"
-	"sync/atomic"
 	"time"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 )
 
-var hahaRunning int32 = 0
-
 type HahaCommand struct{}
 
 func NewHahaCommand() *HahaCommand {
@@ -17,26 +15,25 @@ func NewHahaCommand() *HahaCommand {
 }
 
 func (c *HahaCommand) Execute(ctx *framework.Context) error {
-	// Only allow one instance to run at a time
-	if !atomic.CompareAndSwapInt32(&hahaRunning, 0, 1) {
-		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Warning("Haha is already running"))
-	}
-	defer atomic.StoreInt32(&hahaRunning, 0)
-
-	// Run haha animation in background
-	go func() {
+	// Run haha animation as a job; only one instance may run at a time
+	_, err := ctx.Jobs.Go(ctx, framework.JobOptions{Exclusive: "haha"}, func(job *framework.Job) error {
 		var hahaText string
 		for range 3 {
 			for range 3 {
 				hahaText += "😂"
-				time.Sleep(300 * time.Millisecond)
-				ctx.Handler.EditMessage(ctx.MessageInfo, fmt.Sprintf("```%s```", hahaText))
+				if !job.Sleep(300 * time.Millisecond) {
+					return nil
+				}
+				job.Update(fmt.Sprintf("```%s```", hahaText))
 			}
 		}
-	}()
-
-	return nil
+		return nil
+	})
+	if errors.Is(err, framework.ErrJobRunning) {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Warning(ctx.T("haha.running", "Haha is already running")))
+	}
+	return err
 }
 
 func (c *HahaCommand) Metadata() *framework.Metadata {
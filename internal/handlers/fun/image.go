@@ -1,9 +1,9 @@
 package fun
 
 import (
-	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 )
@@ -17,34 +17,44 @@ func NewImageCommand() *ImageCommand {
 func (c *ImageCommand) Execute(ctx *framework.Context) error {
 	if len(ctx.Args) == 0 {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error("Please provide a prompt for image generation"))
+			framework.Error(ctx.T("image.usage", "Please provide a prompt for image generation")))
 	}
 
 	prompt := ctx.RawArgs
 
-	// Send processing message
-	ctx.Handler.SendResponse(ctx.MessageInfo,
-		framework.Processing(fmt.Sprintf("Generating image: %s", prompt)))
+	// Generate the image as a job so it can be cancelled with /cancel
+	_, err := ctx.Jobs.Go(ctx, framework.JobOptions{
+		Status:  framework.Processing(ctx.T("image.generating", "Generating image: %s", prompt)),
+		Timeout: 5 * time.Minute,
+	}, func(job *framework.Job) error {
+		imageBytes, err := ctx.Handler.GetImageGenerator().GenerateImage(job.Context(), prompt)
+		if err != nil {
+			return fmt.Errorf("failed to generate image: %w", err)
+		}
 
-	// Generate image
-	imageBytes, err := ctx.Handler.GetImageGenerator().GenerateImage(context.Background(), prompt)
-	if err != nil {
-		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error(fmt.Sprintf("Failed to generate image: %v", err)))
-	}
-
-	// Send generated image
-	return ctx.Handler.SendMedia(ctx.MessageInfo, framework.MediaImage, imageBytes, prompt)
+		// Send generated image
+		if err := ctx.Handler.SendMedia(ctx.MessageInfo, framework.MediaImage, imageBytes, prompt); err != nil {
+			return fmt.Errorf("failed to send image: %w", err)
+		}
+		return job.Update(framework.Success(ctx.T("image.done", "Image generated")))
+	})
+	return err
 }
 
 func (c *ImageCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "image",
-		Aliases:      []string{"img", "generate"},
-		Description:  "Generate an AI image from prompt",
-		Category:     "Fun",
-		Usage:        "/image <prompt>",
-		RequireOwner: true,
+		Name:        "image",
+		Aliases:     []string{"img", "generate"},
+		Description: "Generate an AI image from prompt",
+		Category:    "Fun",
+		Usage:       "/image <prompt>",
+		MinRole:     framework.RoleTrusted,
+		RateLimit: &framework.Limit{
+			Rate:  5,
+			Per:   time.Hour,
+			Burst: 2,
+			Scope: framework.ScopeSender,
+		},
 		Examples: []string{
 			"/image a beautiful sunset over mountains",
 			"/image cyberpunk city at night",
@@ -1,7 +1,6 @@
 package fun
 
 import (
-	"context"
 	"fmt"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
@@ -20,22 +19,23 @@ func (c *MemeCommand) Execute(ctx *framework.Context) error {
 	}
 
 	// Show fetching status
-	statusMsg := "🔍 Fetching random meme"
+	statusMsg := "🔍 " + ctx.T("meme.fetching", "Fetching random meme")
 	if subreddit != "" {
-		statusMsg = fmt.Sprintf("🔍 Fetching meme from r/%s", subreddit)
+		statusMsg = "🔍 " + ctx.T("meme.fetching_from", "Fetching meme from r/%s", subreddit)
 	}
 	ctx.Handler.SendResponse(ctx.MessageInfo, statusMsg)
 
 	// Fetch meme
-	memeResp, err := ctx.Handler.GetMemeGenerator().GetRandomMeme(context.Background(), subreddit)
+	memeResp, err := ctx.Handler.GetMemeGenerator().GetRandomMeme(ctx.Context, subreddit)
 	if err != nil {
+		ctx.Logger.Warn().Err(err).Msg("Failed to fetch meme")
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error(fmt.Sprintf("Failed to fetch meme: %v", err)))
+			framework.Error(ctx.T("meme.fetch_failed", "Failed to fetch a meme, please try again later")))
 	}
 
 	if len(memeResp.Memes) == 0 {
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Warning("No memes found"))
+			framework.Warning(ctx.T("meme.none", "No memes found")))
 	}
 
 	meme := memeResp.Memes[0]
@@ -43,8 +43,9 @@ func (c *MemeCommand) Execute(ctx *framework.Context) error {
 	// Download meme image
 	imageData, err := framework.DownloadMedia(meme.URL)
 	if err != nil {
+		ctx.Logger.Warn().Err(err).Str("url", meme.URL).Msg("Failed to download meme")
 		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Error(fmt.Sprintf("Failed to download meme: %v", err)))
+			framework.Error(ctx.T("meme.download_failed", "Failed to download the meme, please try again later")))
 	}
 
 	// Send meme
@@ -1,18 +1,16 @@
 package fun
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
-	"sync/atomic"
 	"time"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
 )
 
-var emojiRunning int32 = 0
-
 type RandmojiCommand struct{}
 
 func NewRandmojiCommand() *RandmojiCommand {
@@ -20,13 +18,6 @@ func NewRandmojiCommand() *RandmojiCommand {
 }
 
 func (c *RandmojiCommand) Execute(ctx *framework.Context) error {
-	// Only allow one instance to run at a time
-	if !atomic.CompareAndSwapInt32(&emojiRunning, 0, 1) {
-		return ctx.Handler.SendResponse(ctx.MessageInfo,
-			framework.Warning("Randmoji is already running"))
-	}
-	defer atomic.StoreInt32(&emojiRunning, 0)
-
 	duration := 10 // default duration
 	if len(ctx.Args) > 0 {
 		if d, err := strconv.Atoi(ctx.Args[0]); err == nil && d > 0 && d <= 10 {
@@ -34,18 +25,24 @@ func (c *RandmojiCommand) Execute(ctx *framework.Context) error {
 		}
 	}
 
-	// Run emoji animation in background
-	go func() {
+	// Run emoji animation as a job; only one instance may run at a time
+	_, err := ctx.Jobs.Go(ctx, framework.JobOptions{Exclusive: "randmoji"}, func(job *framework.Job) error {
 		for i := 0; i < duration; i++ {
 			for j := 0; j < 3; j++ {
-				time.Sleep(500 * time.Millisecond)
+				if !job.Sleep(500 * time.Millisecond) {
+					return nil
+				}
 				emoji := getRandomEmoji()
-				ctx.Handler.EditMessage(ctx.MessageInfo, fmt.Sprintf("```%s```", emoji))
+				job.Update(fmt.Sprintf("```%s```", emoji))
 			}
 		}
-	}()
-
-	return nil
+		return nil
+	})
+	if errors.Is(err, framework.ErrJobRunning) {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Warning(ctx.T("randmoji.running", "Randmoji is already running")))
+	}
+	return err
 }
 
 func (c *RandmojiCommand) Metadata() *framework.Metadata {
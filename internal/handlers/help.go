@@ -1,45 +1,136 @@
 package handlers
 
 import (
+	"strconv"
+	"strings"
+
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
 )
 
+// CommandFilter reports whether a command may run for the sender of msgInfo
+// in its chat, e.g. because it isn't disabled there.
+type CommandFilter interface {
+	CommandAllowed(msgInfo types.MessageInfo, meta *framework.Metadata, role framework.Role) bool
+}
+
 type HelpCommand struct {
 	registry *framework.Registry
+	filter   CommandFilter
 }
 
-func NewHelpCommand(registry *framework.Registry) *HelpCommand {
-	return &HelpCommand{registry: registry}
+// NewHelpCommand creates /help. filter may be nil, in which case help lists
+// every command the caller's role allows.
+func NewHelpCommand(registry *framework.Registry, filter CommandFilter) *HelpCommand {
+	return &HelpCommand{registry: registry, filter: filter}
 }
 
 func (c *HelpCommand) Execute(ctx *framework.Context) error {
-	if len(ctx.Args) > 0 {
-		help := c.registry.GenerateCommandHelp(ctx.Args[0])
-		return ctx.Handler.SendResponse(ctx.MessageInfo, help)
+	args := ctx.Args
+	page := 1
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			page = n
+			args = args[:len(args)-1]
+		}
+	}
+
+	visible := c.visibleTo(ctx)
+
+	switch {
+	case len(args) == 0:
+		lines := c.registry.HelpLines(ctx.Locale, visible)
+		return c.sendPage(ctx, ctx.T("help.title", "📋 Available Commands"), lines, page, "/help",
+			ctx.T("help.hint", "Send `/help <command>` for details, `/help <category>` for a category or `/help search <term>` to search"))
+
+	case strings.EqualFold(args[0], "search") && len(args) > 1:
+		term := strings.Join(args[1:], " ")
+		lines := c.registry.SearchHelpLines(term, visible)
+		if len(lines) == 0 {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Info(ctx.T("help.no_match", "No commands match %q", term)))
+		}
+		return c.sendPage(ctx, ctx.T("help.search_title", "🔎 Commands matching \"%s\"", term), lines, page,
+			"/help search "+term, "")
+	}
+
+	if len(args) == 1 {
+		if title, lines, exists := c.registry.CategoryHelpLines(ctx.Locale, args[0], visible); exists {
+			if len(lines) == 0 {
+				return ctx.Handler.SendResponse(ctx.MessageInfo,
+					framework.Info(ctx.T("help.category_empty", "You can't use any %s commands here", title)))
+			}
+			return c.sendPage(ctx, "📂 "+title, lines, page, "/help "+strings.ToLower(title), "")
+		}
+	}
+
+	path := strings.Join(args, " ")
+	if c.canSee(ctx, path) {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, c.registry.GenerateCommandHelp(ctx.Locale, path))
+	}
+
+	suggestions := c.registry.Suggest(args[0], 3, visible)
+	response := framework.Error(ctx.T("help.unknown", "No command or category named `%s`", path))
+	if len(suggestions) > 0 {
+		response += "\n" + ctx.T("suggest.did_you_mean", "Did you mean %s?", "`/help "+suggestions[0]+"`")
 	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, response)
+}
 
-	help := c.registry.GenerateHelp()
-	return ctx.Handler.SendResponse(ctx.MessageInfo, help)
+// visibleTo lists the commands the sender may run in this chat.
+func (c *HelpCommand) visibleTo(ctx *framework.Context) framework.HelpFilter {
+	byRole := framework.RoleFilter(ctx.Role)
+	return func(cmd framework.Command) bool {
+		if !byRole(cmd) {
+			return false
+		}
+		return c.filter == nil || c.filter.CommandAllowed(ctx.MessageInfo, cmd.Metadata(), ctx.Role)
+	}
+}
+
+// canSee reports whether path names a command whose top-level command the
+// sender may use, so help doesn't reveal commands they can't run.
+func (c *HelpCommand) canSee(ctx *framework.Context, path string) bool {
+	if _, exists := c.registry.Resolve(path); !exists {
+		return false
+	}
+	top, _ := c.registry.Get(strings.Fields(strings.TrimPrefix(path, "/"))[0])
+	return !top.Metadata().Hidden && c.visibleTo(ctx)(top)
+}
+
+func (c *HelpCommand) sendPage(ctx *framework.Context, title string, lines []string, page int, command, hint string) error {
+	lines, page, pages := framework.Paginate(lines, page, framework.HelpPageSize)
+
+	builder := framework.NewResponseBuilder().AddHeading(title)
+	for _, line := range lines {
+		builder.AddLine(line)
+	}
+
+	if pages > 1 {
+		builder.AddEmptyLine().AddLine("_" + ctx.T("help.page", "Page %d of %d", page, pages) + "_")
+		if page < pages {
+			builder.AddLine(ctx.T("help.next_page", "Send `%s %d` for the next page", command, page+1))
+		}
+	}
+	if hint != "" && page == 1 {
+		builder.AddEmptyLine().AddLine(hint)
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
 }
 
 func (c *HelpCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
 		Name:        "help",
-		Description: "Show available commands",
+		Description: "Show the commands you can use",
 		Category:    "Utility",
-		Usage:       "/help [command]",
+		Usage:       "/help [command|category|search <term>] [page]",
+		Essential:   true,
 		Examples: []string{
 			"/help",
-			"/help translate",
-			"/help image",
-		},
-		Parameters: []framework.Parameter{
-			{
-				Name:        "command",
-				Type:        framework.StringParam,
-				Description: "Command to get help for",
-				Required:    false,
-			},
+			"/help download",
+			"/help fun",
+			"/help search video",
+			"/help translation 2",
 		},
 	}
 }
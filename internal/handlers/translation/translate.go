@@ -1,7 +1,6 @@
 package translation
 
 import (
-	"context"
 	"fmt"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
@@ -34,10 +33,12 @@ func (c *TranslateCommand) Execute(ctx *framework.Context) error {
 		return nil
 	}
 
-	// Handle inline translation
-	if !c.handleInlineTranslation(ctx) {
-		return fmt.Errorf("translation failed")
+	// Handle inline translation; failures are already reported in the chat
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("translate.usage", "Quote a message or add text to translate, e.g. /%s Hello", c.langCode)))
 	}
+	c.handleInlineTranslation(ctx)
 	return nil
 }
 
@@ -47,6 +48,7 @@ func (c *TranslateCommand) Metadata() *framework.Metadata {
 		Name:        c.langCode,
 		Description: fmt.Sprintf("Translate to %s", langName),
 		Category:    "Translation",
+		HelpGroup:   "/<lang>",
 		Usage:       fmt.Sprintf("/%s <text>", c.langCode),
 		Examples: []string{
 			fmt.Sprintf("/%s Hello world", c.langCode),
@@ -68,26 +70,25 @@ func (c *TranslateCommand) handleMediaCaptionTranslation(ctx *framework.Context)
 	textToTranslate := ctx.RawArgs
 	detectedLang, err := ctx.Handler.GetLangDetector().DetectLanguage(textToTranslate)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error("Could not detect source language"))
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.no_language", "Could not detect source language")))
 		return true
 	}
 
-	fmt.Printf("[TRANSLATE] Media caption translation: detected=%s, target=%s, text=%s\n", detectedLang, c.langCode, textToTranslate)
+	ctx.Logger.Debug().Str("from", detectedLang).Str("to", c.langCode).Msg("Translating media caption")
 
 	translated, err := ctx.Handler.GetTranslator().TranslateText(
-		context.Background(), textToTranslate, detectedLang, c.langCode)
+		ctx.Context, textToTranslate, detectedLang, c.langCode)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(fmt.Sprintf("Translation failed: %v", err)))
+		ctx.Logger.Warn().Err(err).Msg("Translation failed")
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.failed", "Translation failed, please try again later")))
 		return true
 	}
 
-	fmt.Printf("[TRANSLATE] Media caption translated: %s\n", translated)
-
 	// For media messages from the user, we need to edit the caption
 	if ctx.MessageInfo.IsFromMe {
 		// Use the new method that passes the original message for proper media caption editing
 		if err := ctx.Handler.EditMessageWithOriginal(ctx.MessageInfo, translated, ctx.Message); err != nil {
-			fmt.Printf("[TRANSLATE] Caption edit failed: %v\n", err)
+			ctx.Logger.Warn().Err(err).Msg("Caption edit failed")
 			// Fallback to sending as text response
 			ctx.Handler.SendResponse(ctx.MessageInfo, translated)
 		}
@@ -111,41 +112,31 @@ func (c *TranslateCommand) handleQuotedMessageTranslation(ctx *framework.Context
 
 	quotedText := extractText(quotedMsg)
 	if quotedText == "" {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Warning("Quoted message has no translatable text"))
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Warning(ctx.T("translate.no_text", "Quoted message has no translatable text")))
 		return true
 	}
 
 	detectedLang, err := ctx.Handler.GetLangDetector().DetectLanguage(quotedText)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error("Could not detect source language"))
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.no_language", "Could not detect source language")))
 		return true
 	}
 
-	fmt.Printf("[TRANSLATE] Quoted message translation: detected=%s, target=%s, text=%s\n", detectedLang, c.langCode, quotedText)
+	ctx.Logger.Debug().Str("from", detectedLang).Str("to", c.langCode).Str("media", msgType).Msg("Translating quoted message")
 
 	translated, err := ctx.Handler.GetTranslator().TranslateText(
-		context.Background(), quotedText, detectedLang, c.langCode)
+		ctx.Context, quotedText, detectedLang, c.langCode)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(fmt.Sprintf("Translation failed: %v", err)))
+		ctx.Logger.Warn().Err(err).Msg("Translation failed")
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.failed", "Translation failed, please try again later")))
 		return true
 	}
 
-	fmt.Printf("[TRANSLATE] Translation result: %s\n", translated)
-
+	// Linking the translation to the quoted message keeps it up to date when
+	// that message is edited later on
 	quotedMsgID := ctx.Message.GetExtendedTextMessage().GetContextInfo().GetStanzaID()
-	isMedia := isMediaMessage(quotedMsg)
-
-	if ctx.MessageInfo.IsFromMe && isMedia && quotedMsgID != "" {
-		fmt.Printf("Attempting to edit %s caption\n", msgType)
-		if err := ctx.Handler.EditMessage(ctx.MessageInfo, translated); err != nil {
-			fmt.Printf("Quoted %s caption edit failed: %v\n", msgType, err)
-		}
-	} else if ctx.MessageInfo.IsFromMe {
-		if err := ctx.Handler.EditMessage(ctx.MessageInfo, translated); err != nil {
-			fmt.Printf("Edit failed: %v\n", err)
-		}
-	} else {
-		ctx.Handler.SendResponse(ctx.MessageInfo, translated)
+	if err := ctx.Handler.SendTranslation(ctx.MessageInfo, quotedMsgID, c.langCode, translated); err != nil {
+		ctx.Logger.Error().Err(err).Str("media", msgType).Msg("Sending translation failed")
 	}
 
 	return true
@@ -160,22 +151,23 @@ func (c *TranslateCommand) handleInlineTranslation(ctx *framework.Context) bool
 
 	detectedLang, err := ctx.Handler.GetLangDetector().DetectLanguage(textToTranslate)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error("Could not detect source language"))
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.no_language", "Could not detect source language")))
 		return false
 	}
 
-	fmt.Printf("[TRANSLATE] Inline translation: detected=%s, target=%s, text=%s\n", detectedLang, c.langCode, textToTranslate)
+	ctx.Logger.Debug().Str("from", detectedLang).Str("to", c.langCode).Msg("Translating inline text")
 
 	translated, err := ctx.Handler.GetTranslator().TranslateText(
-		context.Background(), textToTranslate, detectedLang, c.langCode)
+		ctx.Context, textToTranslate, detectedLang, c.langCode)
 	if err != nil {
-		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(fmt.Sprintf("Translation failed: %v", err)))
+		ctx.Logger.Warn().Err(err).Msg("Translation failed")
+		ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("translate.failed", "Translation failed, please try again later")))
 		return false
 	}
 
-	fmt.Printf("[TRANSLATE] Translation result: %s\n", translated)
-
-	ctx.Handler.SendResponse(ctx.MessageInfo, translated)
+	if err := ctx.Handler.SendTranslation(ctx.MessageInfo, ctx.MessageInfo.ID, c.langCode, translated); err != nil {
+		ctx.Logger.Error().Err(err).Msg("Sending translation failed")
+	}
 	return true
 }
 
@@ -1,54 +1,250 @@
 package utility
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+	"go.mau.fi/whatsmeow/types"
 )
 
-type AfkModeController interface {
-	SetAfkMode(bool)
-	IsAfkMode() bool
+// AfkStatus describes the current AFK mode.
+type AfkStatus struct {
+	Since   time.Time
+	Until   time.Time
+	Message string
+	// Senders and Messages count who wrote so far
+	Senders  int
+	Messages int
+}
+
+// AfkSender is someone who wrote while AFK.
+type AfkSender struct {
+	Name  string
+	Count int
+	Last  time.Time
+	// Direct is set for direct messages, Groups lists the groups the sender
+	// mentioned the account in and GroupNames their subjects
+	Direct     bool
+	Groups     []types.JID
+	GroupNames []string
+}
+
+// AfkSummary lists who wrote while AFK, latest first. Others counts the
+// messages of senders beyond the ones kept.
+type AfkSummary struct {
+	Since   time.Time
+	Senders []AfkSender
+	Others  int
+}
+
+// AfkManager turns AFK mode on and off. While AFK, direct messages and
+// mentions in groups are answered with the AFK message, except in excluded
+// chats and from excluded users.
+type AfkManager interface {
+	TimezoneResolver
+	StartAfk(until time.Time, message string)
+	StopAfk() (AfkSummary, bool)
+	AfkStatus() (AfkStatus, bool)
+	SetAfkExcluded(jid types.JID, excluded bool) error
+	AfkExcluded() ([]types.JID, error)
 }
 
 type AfkCommand struct {
-	eventHandler AfkModeController
+	afk AfkManager
 }
 
 type NoAfkCommand struct {
-	eventHandler AfkModeController
+	afk AfkManager
 }
 
-func NewAfkCommand(eventHandler AfkModeController) *AfkCommand {
-	return &AfkCommand{
-		eventHandler: eventHandler,
+func NewAfkCommand(afk AfkManager) *AfkCommand {
+	return &AfkCommand{afk: afk}
+}
+
+func NewNoAfkCommand(afk AfkManager) *NoAfkCommand {
+	return &NoAfkCommand{afk: afk}
+}
+
+func (c *AfkCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) > 0 {
+		switch strings.ToLower(ctx.Args[0]) {
+		case "status":
+			return c.status(ctx)
+		case "exclude":
+			return c.exclude(ctx, true)
+		case "include":
+			return c.exclude(ctx, false)
+		case "excluded":
+			return c.excluded(ctx)
+		}
+	}
+
+	// A leading time is the expiry, anything else is the message
+	loc := c.afk.UserLocation(ctx.MessageInfo)
+	var until time.Time
+	message := ctx.RawArgs
+	if at, used, err := framework.ParseWhen(ctx.Args, time.Now().In(loc)); err == nil {
+		until = at
+		message = ctx.RawRest(used)
 	}
+	message = strings.TrimSpace(message)
+
+	c.afk.StartAfk(until, message)
+
+	response := ctx.T("afk.started", "✅ AFK mode enabled. Direct messages and mentions in groups get your AFK message.")
+	if !until.IsZero() {
+		response += "\n" + ctx.T("afk.until", "It ends on %s (in %s).", formatScheduleTime(until), formatUntil(time.Until(until)))
+	}
+	if message != "" {
+		response += "\n" + ctx.T("afk.custom", "Message: _%s_", preview(message, 80))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(response))
 }
 
-func NewNoAfkCommand(eventHandler AfkModeController) *NoAfkCommand {
-	return &NoAfkCommand{
-		eventHandler: eventHandler,
+func (c *AfkCommand) status(ctx *framework.Context) error {
+	status, ok := c.afk.AfkStatus()
+	if !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Info(ctx.T("afk.off", "AFK mode is off")))
+	}
+
+	loc := c.afk.UserLocation(ctx.MessageInfo)
+	builder := framework.NewResponseBuilder().
+		AddHeading(ctx.T("afk.status_title", "💤 AFK Mode")).
+		AddLine(ctx.T("afk.since", "Away since %s (%s ago)", formatScheduleTime(status.Since.In(loc)), formatUntil(time.Since(status.Since))))
+	if !status.Until.IsZero() {
+		builder.AddLine(ctx.T("afk.until", "It ends on %s (in %s).", formatScheduleTime(status.Until.In(loc)), formatUntil(time.Until(status.Until))))
+	}
+	if status.Message != "" {
+		builder.AddLine(ctx.T("afk.custom", "Message: _%s_", preview(status.Message, 80)))
 	}
+	builder.AddLine(ctx.T("afk.count", "%d messages from %d senders so far", status.Messages, status.Senders))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
 }
 
-func (c *AfkCommand) Execute(ctx *framework.Context) error {
-	c.eventHandler.SetAfkMode(true)
-	response := "✅ AFK mode enabled. Anyone messaging will receive your AFK message."
-	return ctx.Handler.SendResponse(ctx.MessageInfo, response)
+func (c *AfkCommand) exclude(ctx *framework.Context, excluded bool) error {
+	target, err := afkTarget(ctx)
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error()))
+	}
+	if err := c.afk.SetAfkExcluded(target, excluded); err != nil {
+		return fmt.Errorf("failed to update AFK exclusions: %w", err)
+	}
+
+	if excluded {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(ctx.T("afk.excluded", "%s won't get AFK replies", describeJID(target))))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("afk.included", "%s gets AFK replies again", describeJID(target))))
+}
+
+func (c *AfkCommand) excluded(ctx *framework.Context) error {
+	excluded, err := c.afk.AfkExcluded()
+	if err != nil {
+		return err
+	}
+	if len(excluded) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("afk.none_excluded", "Nobody is excluded from AFK replies")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("afk.excluded_title", "🔕 Excluded from AFK Replies"))
+	for _, jid := range excluded {
+		builder.AddLine("• " + describeJID(jid))
+	}
+	builder.AddEmptyLine().AddLine(ctx.T("afk.include_hint", "Use `/afk include <@user|number|here>` to remove one"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
 }
 
 func (c *NoAfkCommand) Execute(ctx *framework.Context) error {
-	c.eventHandler.SetAfkMode(false)
-	response := "❌ AFK mode disabled."
+	summary, ok := c.afk.StopAfk()
+	if !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Info(ctx.T("afk.off", "AFK mode is off")))
+	}
+
+	response := ctx.T("afk.disabled", "❌ AFK mode disabled.") + "\n\n" + AfkSummaryText(ctx.Locale, c.afk.UserLocation(ctx.MessageInfo), summary)
 	return ctx.Handler.SendResponse(ctx.MessageInfo, response)
 }
 
+// AfkSummaryText renders who wrote while AFK, for /noafk and for the notice
+// sent when AFK mode expires. Times are shown in loc.
+func AfkSummaryText(l *i18n.Localizer, loc *time.Location, summary AfkSummary) string {
+	away := formatUntil(time.Since(summary.Since))
+	if len(summary.Senders) == 0 && summary.Others == 0 {
+		return l.T("afk.summary_empty", "Nobody wrote while you were away (%s).", away)
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(l.T("afk.summary_title", "📬 While you were away (%s)", away))
+	for _, sender := range summary.Senders {
+		var where []string
+		if sender.Direct {
+			where = append(where, l.T("afk.direct", "direct"))
+		}
+		where = append(where, sender.GroupNames...)
+		builder.AddLine(l.T("afk.summary_line", "• *%s* - %d messages (%s), last at %s",
+			sender.Name, sender.Count, strings.Join(where, ", "), sender.Last.In(loc).Format("15:04")))
+	}
+	if summary.Others > 0 {
+		builder.AddLine(l.T("afk.summary_others", "• %d more messages from others", summary.Others))
+	}
+	return builder.Build()
+}
+
+// afkTarget resolves the chat or user to exclude: a mention, a phone number,
+// the author of the replied message, or "here" (also the default) for the
+// current chat.
+func afkTarget(ctx *framework.Context) (types.JID, error) {
+	contextInfo := ctx.Message.GetExtendedTextMessage().GetContextInfo()
+	if mentioned := contextInfo.GetMentionedJID(); len(mentioned) > 0 {
+		return types.ParseJID(mentioned[0])
+	}
+
+	if len(ctx.Args) > 1 && !strings.EqualFold(ctx.Args[1], "here") {
+		number := strings.TrimPrefix(strings.TrimPrefix(ctx.Args[1], "@"), "+")
+		if strings.Contains(number, "@") {
+			return types.ParseJID(number)
+		}
+		if strings.Trim(number, "0123456789") != "" {
+			return types.EmptyJID, fmt.Errorf("%q is not a phone number", ctx.Args[1])
+		}
+		return types.NewJID(number, types.DefaultUserServer), nil
+	}
+
+	if len(ctx.Args) == 1 {
+		if participant := contextInfo.GetParticipant(); participant != "" {
+			return types.ParseJID(participant)
+		}
+	}
+	return ctx.MessageInfo.Chat.ToNonAD(), nil
+}
+
+// describeJID shows users by phone number and other chats by JID.
+func describeJID(jid types.JID) string {
+	if jid.Server == types.DefaultUserServer {
+		return "+" + jid.User
+	}
+	return jid.String()
+}
+
 func (c *AfkCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
-		Name:         "afk",
-		Aliases:      []string{},
-		Description:  "Enable AFK mode to send automatic responses",
-		Category:     "Utility",
-		Usage:        "/afk",
-		Examples:     []string{"/afk"},
+		Name:        "afk",
+		Description: "Auto-reply to direct messages and mentions while you're away",
+		Category:    "Utility",
+		Usage:       "/afk [" + framework.WhenUsage + "] [message] | status | exclude|include [@user|number|here] | excluded",
+		Examples: []string{
+			"/afk",
+			"/afk 2h In a meeting, back soon",
+			"/afk 18:00 On a flight",
+			"/afk exclude here",
+			"/afk exclude @mom",
+			"/afk excluded",
+		},
 		RequireOwner: true,
 	}
 }
@@ -56,11 +252,10 @@ func (c *AfkCommand) Metadata() *framework.Metadata {
 func (c *NoAfkCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
 		Name:         "noafk",
-		Aliases:      []string{},
-		Description:  "Disable AFK mode",
+		Description:  "Disable AFK mode and see who wrote while you were away",
 		Category:     "Utility",
 		Usage:        "/noafk",
 		Examples:     []string{"/noafk"},
 		RequireOwner: true,
 	}
-}
\ No newline at end of file
+}
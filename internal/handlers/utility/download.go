@@ -1,176 +1,221 @@
 package utility
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 	"github.com/lrstanley/go-ytdlp"
+	"go.mau.fi/whatsmeow/types"
 )
 
-type DownloadCommand struct {
-	mu               sync.Mutex
-	isDownloading    bool
-	lastDownloadTime time.Time
+// downloadQueue is the job queue all downloads of an account wait in.
+const downloadQueue = "download"
+
+// defaultQuality is the highest video height downloaded without --quality.
+const defaultQuality = 720
+
+// downloadQualities are the heights --quality accepts.
+var downloadQualities = []int{360, 480, 720, 1080}
+
+// DownloadRequest is what a /download fetches and how it is sent. It is
+// stored while the download is queued or running.
+type DownloadRequest struct {
+	URL string `json:"url"`
+	// Audio extracts the audio as mp3; Voice as opus, sent as voice note
+	Audio bool `json:"audio,omitempty"`
+	Voice bool `json:"voice,omitempty"`
+	// Quality is the highest video height, 0 for defaultQuality
+	Quality int `json:"quality,omitempty"`
+	// Document sends the file as document whatever its type
+	Document bool `json:"document,omitempty"`
+}
+
+func (r DownloadRequest) audioOnly() bool {
+	return r.Audio || r.Voice
+}
+
+// format returns the yt-dlp format selection. Video prefers H.264 with AAC
+// audio, which WhatsApp plays everywhere, merged into an mp4.
+func (r DownloadRequest) format() string {
+	if r.audioOnly() {
+		return "bestaudio/best"
+	}
+	height := r.Quality
+	if height <= 0 {
+		height = defaultQuality
+	}
+	return fmt.Sprintf("bestvideo[height<=%[1]d][vcodec^=avc1]+bestaudio[ext=m4a]/best[height<=%[1]d]/best", height)
 }
 
-var downloadInstance = &DownloadCommand{}
+// DownloadStore keeps queued and running downloads so they can be resumed
+// after a restart.
+type DownloadStore interface {
+	SaveDownload(msgInfo types.MessageInfo, req DownloadRequest) (int64, error)
+	SetDownloadStatus(id int64, statusID types.MessageID) error
+	DeleteDownload(id int64) error
+}
+
+type DownloadCommand struct {
+	store DownloadStore
+}
 
-func NewDownloadCommand() *DownloadCommand {
-	return downloadInstance
+func NewDownloadCommand(store DownloadStore) *DownloadCommand {
+	return &DownloadCommand{store: store}
 }
 
 func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 	if len(ctx.Args) == 0 {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error("Please provide a URL to download"))
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("download.usage", "Please provide a URL to download")))
 	}
 
-	// Check rate limiting
-	c.mu.Lock()
-
-	// Check if download is already in progress
-	if c.isDownloading {
-		c.mu.Unlock()
-		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Warning("⏳ A download is already in progress. Please wait for it to complete."))
+	queue := ctx.Jobs.Queue(downloadQueue, config.AppConfig.DownloadWorkers)
+	limit := config.AppConfig.DownloadUserLimit
+	if ctx.Role < framework.RoleOwner && limit > 0 && queue.CountStartedBy(ctx.MessageInfo) >= limit {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Warning(
+			ctx.T("download.limit", "You already have %d downloads queued or running. Wait for one to finish or /cancel it.", limit)))
 	}
 
-	// Check cooldown period (1 minute)
-	if !c.lastDownloadTime.IsZero() {
-		timeSinceLastDownload := time.Since(c.lastDownloadTime)
-		if timeSinceLastDownload < 5*time.Second {
-			remainingTime := 5*time.Second - timeSinceLastDownload
-			c.mu.Unlock()
-			return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Warning(fmt.Sprintf("⏱️ Please wait %d seconds before downloading again.", int(remainingTime.Seconds()))))
-		}
+	req := DownloadRequest{
+		URL:      ctx.Args[0],
+		Audio:    ctx.Flags.Bool("audio"),
+		Voice:    ctx.Flags.Bool("voice"),
+		Quality:  ctx.Flags.Int("quality"),
+		Document: ctx.Flags.Bool("doc"),
+	}
+	if !slices.Contains(downloadQualities, req.Quality) {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(
+			ctx.T("download.quality", "Quality must be one of 360, 480, 720 or 1080")))
 	}
 
-	// Mark as downloading
-	c.isDownloading = true
-	c.mu.Unlock()
-
-	// Ensure we mark as not downloading when done
-	defer func() {
-		c.mu.Lock()
-		c.isDownloading = false
-		c.lastDownloadTime = time.Now()
-		c.mu.Unlock()
-		fmt.Printf("[DOWNLOAD] Download completed, cooldown started\n")
-	}()
+	id, err := c.store.SaveDownload(ctx.MessageInfo, req)
+	if err != nil {
+		// The download still runs, it just won't survive a restart
+		ctx.Logger.Warn().Err(err).Msg("Failed to save download")
+		id = 0
+	}
+	return c.start(ctx, id, req, "")
+}
 
-	url := ctx.Args[0]
-	fmt.Printf("[DOWNLOAD] Starting download for URL: %s\n", url)
+// Resume queues a download saved before a restart again, reusing its
+// status message.
+func (c *DownloadCommand) Resume(ctx *framework.Context, id int64, req DownloadRequest, statusID types.MessageID) error {
+	return c.start(ctx, id, req, statusID)
+}
 
-	// Send initial processing message that we'll edit later
-	initialMsg := framework.Processing("Starting download...")
-	err := ctx.Handler.SendResponse(ctx.MessageInfo, initialMsg)
+// start queues the download as a job, so it can be listed with /jobs and
+// cancelled with /cancel. The saved download is removed once the job ends,
+// unless it was interrupted by a shutdown.
+func (c *DownloadCommand) start(ctx *framework.Context, id int64, req DownloadRequest, statusID types.MessageID) error {
+	job, err := ctx.Jobs.Go(ctx, framework.JobOptions{
+		Status:   framework.Processing(ctx.T("download.starting", "Starting download...")),
+		StatusID: statusID,
+		Timeout:  10 * time.Minute,
+		Queue:    ctx.Jobs.Queue(downloadQueue, config.AppConfig.DownloadWorkers),
+		OnDone: func(job *framework.Job) {
+			if id == 0 || job.Interrupted() {
+				return
+			}
+			if err := c.store.DeleteDownload(id); err != nil {
+				ctx.Logger.Warn().Err(err).Int64("download", id).Msg("Failed to delete finished download")
+			}
+		},
+	}, func(job *framework.Job) error {
+		return c.download(ctx, job, req)
+	})
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] Failed to send initial message: %v\n", err)
+		return err
 	}
 
+	if id != 0 && statusID == "" {
+		if err := c.store.SetDownloadStatus(id, job.StatusID()); err != nil {
+			ctx.Logger.Warn().Err(err).Int64("download", id).Msg("Failed to save download status message")
+		}
+	}
+	return nil
+}
+
+func (c *DownloadCommand) download(ctx *framework.Context, job *framework.Job, req DownloadRequest) error {
+	url := req.URL
+	ctx.Logger.Info().Str("url", url).Bool("audio", req.audioOnly()).Int("quality", req.Quality).Msg("Starting download")
+
 	// Create temporary directory for downloads
 	tempDir, err := os.MkdirTemp("", "whatsapp-download-*")
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] Failed to create temp directory: %v\n", err)
-		// Reset download state on early error
-		c.mu.Lock()
-		c.isDownloading = false
-		c.mu.Unlock()
-		errorMsg := framework.Error("Failed to create temp directory")
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		ctx.Logger.Error().Err(err).Msg("Failed to create temp directory")
+		errorMsg := framework.Error(ctx.T("download.temp_dir", "Failed to create temp directory"))
+		job.Update(errorMsg)
 		return nil
 	}
 	defer func() {
-		fmt.Printf("[DOWNLOAD] Cleaning up temp directory: %s\n", tempDir)
 		os.RemoveAll(tempDir)
 	}()
-	fmt.Printf("[DOWNLOAD] Created temp directory: %s\n", tempDir)
 
 	// Update message to show downloading
-	processingMsg := framework.Processing("Downloading media...")
-	ctx.Handler.EditMessage(ctx.MessageInfo, processingMsg)
+	processingMsg := framework.Processing(ctx.T("download.downloading", "Downloading media..."))
+	job.Update(processingMsg)
 
 	// Configure output template
 	outputTemplate := filepath.Join(tempDir, "download.%(ext)s")
-	fmt.Printf("[DOWNLOAD] Output template: %s\n", outputTemplate)
 
 	// Initialize ytdlp with options
-	dl := ytdlp.New().
-		Format("best[height<=720]/best"). // Better format selection
-		NoPlaylist().                     // Download only single video, not playlist
-		RestrictFilenames().              // Safe filenames
+	dl := newYtdlp().
+		Format(req.format()).
+		RestrictFilenames(). // Safe filenames
 		Output(outputTemplate).
-		NoCheckCertificates(). // Skip certificate verification
-		Verbose()              // Enable verbose logging
-
-	// Check if URL is YouTube
-	isYouTube := strings.Contains(url, "youtube.com") || strings.Contains(url, "youtu.be")
-
-	// For all other sites, try to use cookies if available
-	dl = dl.CookiesFromBrowser("firefox:/root/profile/")
-	dl = dl.AddHeaders(fmt.Sprintf("User-Agent:%s", os.Getenv("USER_AGENT")))
+		Verbose() // Enable verbose logging
+	if req.Voice {
+		dl = dl.ExtractAudio().AudioFormat("opus")
+	} else if req.Audio {
+		dl = dl.ExtractAudio().AudioFormat("mp3")
+	} else {
+		dl = dl.MergeOutputFormat("mp4")
+	}
 
 	// Download the media
-	fmt.Printf("[DOWNLOAD] Running yt-dlp...\n")
-	result, err := dl.Run(context.Background(), url)
+	result, err := dl.Run(job.Context(), url)
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] yt-dlp failed: %v\n", err)
-
-		// Check for common errors
-		errStr := err.Error()
-
-		var errorMsg string
-		if isYouTube && (strings.Contains(errStr, "Sign in to confirm") || strings.Contains(errStr, "age")) {
-			errorMsg = framework.Error("❌ This video is age-restricted.\n\nTo download it, set YOUTUBE_VISITOR_DATA environment variable.\nSee README for instructions.")
-		} else if strings.Contains(errStr, "This content isn't available") {
-			errorMsg = framework.Error("❌ Content unavailable. Video may be private, deleted, or region-blocked.")
-		} else if !isYouTube && (strings.Contains(errStr, "login") || strings.Contains(errStr, "private") || strings.Contains(errStr, "authenticate")) {
-			errorMsg = framework.Error("❌ This content requires authentication.\n\nExport cookies from your browser and set COOKIES_PATH.\nSee README for instructions.")
-		} else {
-			errorMsg = framework.Error(fmt.Sprintf("Download failed: %v", err))
+		ctx.Logger.Warn().Err(err).Msg("yt-dlp failed")
+		if job.Context().Err() != nil {
+			// Cancelled or timed out; the job reports it
+			return nil
 		}
-
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		job.Update(ytdlpErrorMessage(ctx, url, err))
 		return nil
 	}
 
 	if result != nil {
-		fmt.Printf("[DOWNLOAD] yt-dlp exit code: %d\n", result.ExitCode)
-		if result.Stdout != "" {
-			fmt.Printf("[DOWNLOAD] yt-dlp stdout:\n%s\n", result.Stdout)
-		}
-		if result.Stderr != "" {
-			fmt.Printf("[DOWNLOAD] yt-dlp stderr:\n%s\n", result.Stderr)
-		}
+		ctx.Logger.Debug().Int("exit_code", result.ExitCode).Str("stdout", result.Stdout).Str("stderr", result.Stderr).Msg("yt-dlp finished")
 	}
 
 	// Wait a moment for file to be fully written
 	time.Sleep(500 * time.Millisecond)
 
 	// Update message to show processing
-	processingMsg = framework.Processing("Processing downloaded file...")
-	ctx.Handler.EditMessage(ctx.MessageInfo, processingMsg)
+	processingMsg = framework.Processing(ctx.T("download.processing", "Processing downloaded file..."))
+	job.Update(processingMsg)
 
 	// Find the downloaded file
 	files, err := filepath.Glob(filepath.Join(tempDir, "download.*"))
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] Glob error: %v\n", err)
-		errorMsg := framework.Error("Error finding downloaded file")
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		ctx.Logger.Error().Err(err).Msg("Failed to look for the downloaded file")
+		errorMsg := framework.Error(ctx.T("download.find_failed", "Error finding downloaded file"))
+		job.Update(errorMsg)
 		return nil
 	}
 
-	fmt.Printf("[DOWNLOAD] Found %d files in temp directory\n", len(files))
+	ctx.Logger.Debug().Int("files", len(files)).Msg("Looked for downloaded files")
 	for i, f := range files {
 		info, _ := os.Stat(f)
 		if info != nil {
-			fmt.Printf("[DOWNLOAD] File %d: %s (size: %d bytes)\n", i+1, f, info.Size())
+			ctx.Logger.Debug().Int("index", i+1).Str("file", f).Int64("bytes", info.Size()).Msg("Downloaded file")
 		}
 	}
 
@@ -187,7 +232,7 @@ func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 			altFiles, _ := filepath.Glob(pattern)
 			if len(altFiles) > 0 {
 				files = altFiles
-				fmt.Printf("[DOWNLOAD] Found files with pattern %s\n", pattern)
+				ctx.Logger.Debug().Str("pattern", pattern).Msg("Found files with fallback pattern")
 				break
 			}
 		}
@@ -195,55 +240,54 @@ func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 		if len(files) == 0 {
 			// List all files in temp directory for debugging
 			allFiles, _ := os.ReadDir(tempDir)
-			fmt.Printf("[DOWNLOAD] All files in temp directory:\n")
+			names := make([]string, 0, len(allFiles))
 			for _, f := range allFiles {
-				fmt.Printf("[DOWNLOAD]   - %s\n", f.Name())
+				names = append(names, f.Name())
 			}
-			errorMsg := framework.Error("Downloaded file not found")
-			ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+			ctx.Logger.Warn().Strs("files", names).Msg("No downloaded file found")
+			errorMsg := framework.Error(ctx.T("download.not_found", "Downloaded file not found"))
+			job.Update(errorMsg)
 			return nil
 		}
 	}
 	outputFile := files[0]
-	fmt.Printf("[DOWNLOAD] Selected file: %s\n", outputFile)
 
 	// Get file info
 	fileInfo, err := os.Stat(outputFile)
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] Failed to stat file: %v\n", err)
-		errorMsg := framework.Error("Failed to access downloaded file")
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		ctx.Logger.Error().Err(err).Msg("Failed to stat downloaded file")
+		errorMsg := framework.Error(ctx.T("download.stat_failed", "Failed to access downloaded file"))
+		job.Update(errorMsg)
 		return nil
 	}
-	fmt.Printf("[DOWNLOAD] File size: %d bytes (%.2f MB)\n", fileInfo.Size(), float64(fileInfo.Size())/(1024*1024))
+	ctx.Logger.Debug().Str("file", outputFile).Int64("bytes", fileInfo.Size()).Msg("Selected downloaded file")
 
 	// Read the file
 	data, err := os.ReadFile(outputFile)
 	if err != nil {
-		fmt.Printf("[DOWNLOAD] Failed to read file: %v\n", err)
-		errorMsg := framework.Error("Failed to read downloaded file")
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		ctx.Logger.Error().Err(err).Msg("Failed to read downloaded file")
+		errorMsg := framework.Error(ctx.T("download.read_failed", "Failed to read downloaded file"))
+		job.Update(errorMsg)
 		return nil
 	}
-	fmt.Printf("[DOWNLOAD] Read %d bytes from file\n", len(data))
 
 	// Check if file is actually empty
 	if len(data) == 0 {
-		fmt.Printf("[DOWNLOAD] ERROR: File is empty!\n")
-		errorMsg := framework.Error("Downloaded file is empty")
-		ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+		ctx.Logger.Warn().Msg("Downloaded file is empty")
+		errorMsg := framework.Error(ctx.T("download.empty", "Downloaded file is empty"))
+		job.Update(errorMsg)
 		return nil
 	}
 
 	// Check file size (WhatsApp has limits)
 	const maxSize = 16 * 1024 * 1024 // 16MB limit for WhatsApp
-	if len(data) > maxSize {
-		fmt.Printf("[DOWNLOAD] File too large: %d bytes\n", len(data))
-		// For large files, we'll send as document instead
-		fmt.Printf("[DOWNLOAD] File exceeds 16MB limit, sending as document...\n")
-
-		// Prepare caption with size info
-		caption := fmt.Sprintf("📥 Downloaded from: %s\n\n📎 File is %.1f MB (exceeds 16MB limit for media)", url, float64(len(data))/(1024*1024))
+	if len(data) > maxSize || req.Document {
+		// For large files, or when asked for, we'll send as document instead
+		caption := ctx.T("download.caption", "📥 Downloaded from: %s", url)
+		if len(data) > maxSize {
+			ctx.Logger.Info().Int("bytes", len(data)).Msg("File exceeds 16MB limit, sending as document")
+			caption += "\n\n" + ctx.T("download.too_large", "📎 File is %.1f MB (exceeds 16MB limit for media)", float64(len(data))/(1024*1024))
+		}
 
 		// Upload as document with proper filename to preserve extension
 		filename := filepath.Base(outputFile)
@@ -252,51 +296,62 @@ func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 		}
 
 		uploader := framework.NewMediaUploader(ctx.Handler.GetClient())
-		resp, err := uploader.UploadDocument(ctx.Context, data, filename)
+		resp, err := uploader.UploadDocument(job.Context(), data, filename)
 		if err != nil {
-			fmt.Printf("[DOWNLOAD] Document upload failed: %v\n", err)
-			errorMsg := framework.Error(fmt.Sprintf("Failed to upload document: %v", err))
-			ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+			ctx.Logger.Error().Err(err).Msg("Document upload failed")
+			errorMsg := framework.Error(ctx.T("download.upload_failed", "Failed to upload the file, please try again later"))
+			job.Update(errorMsg)
 			return nil
 		}
 
 		// Send document with caption
 		err = ctx.Handler.SendDocument(ctx.MessageInfo, resp, caption)
 		if err != nil {
-			fmt.Printf("[DOWNLOAD] Failed to send document message: %v\n", err)
+			ctx.Logger.Error().Err(err).Msg("Failed to send document message")
 			return err
 		}
-		fmt.Printf("[DOWNLOAD] Large file sent as document successfully\n")
 		return nil
 	}
 
 	// Prepare caption
-	caption := fmt.Sprintf("📥 Downloaded from: %s", url)
+	caption := ctx.T("download.caption", "📥 Downloaded from: %s", url)
 
 	// Send based on type using the media uploader's UploadAndSend methods
 	uploader := framework.NewMediaUploader(ctx.Handler.GetClient())
 	extension := strings.ToLower(filepath.Ext(outputFile))
-	fmt.Printf("[DOWNLOAD] File extension: %s\n", extension)
 
-	if extension == ".mp4" || extension == ".webm" || extension == ".mkv" || extension == ".avi" {
-		fmt.Printf("[DOWNLOAD] Uploading and sending as video...\n")
+	if req.audioOnly() {
+		// Audio messages have no caption
+		mimeType := "audio/mpeg"
+		if extension == ".opus" || extension == ".ogg" {
+			mimeType = "audio/ogg; codecs=opus"
+		}
+		err := uploader.UploadAndSendAudio(job.Context(), ctx.MessageInfo.Chat, data, mimeType, req.Voice)
+		if err != nil {
+			ctx.Logger.Error().Err(err).Msg("Audio upload failed")
+			errorMsg := framework.Error(ctx.T("download.upload_failed", "Failed to upload the file, please try again later"))
+			job.Update(errorMsg)
+			return nil
+		}
+		return nil
+	} else if extension == ".mp4" || extension == ".webm" || extension == ".mkv" || extension == ".avi" {
 		// Upload and send as video
-		err := uploader.UploadAndSendVideo(ctx.Context, ctx.MessageInfo.Chat, data, caption)
+		err := uploader.UploadAndSendVideo(job.Context(), ctx.MessageInfo.Chat, data, caption)
 		if err != nil {
-			fmt.Printf("[DOWNLOAD] Video upload/send failed: %v\n", err)
-			errorMsg := framework.Error(fmt.Sprintf("Failed to upload/send video: %v", err))
-			ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+			ctx.Logger.Error().Err(err).Msg("Video upload failed")
+			errorMsg := framework.Error(ctx.T("download.upload_failed", "Failed to upload the file, please try again later"))
+			job.Update(errorMsg)
 			return nil
 		}
-		fmt.Printf("[DOWNLOAD] Video sent successfully\n")
 		return nil
 	} else {
 		// Upload and send as image or document
 		if isImageFile(outputFile) {
-			err := uploader.UploadAndSendImage(ctx.Context, ctx.MessageInfo.Chat, data, caption)
+			err := uploader.UploadAndSendImage(job.Context(), ctx.MessageInfo.Chat, data, caption)
 			if err != nil {
-				errorMsg := framework.Error(fmt.Sprintf("Failed to upload/send image: %v", err))
-				ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+				ctx.Logger.Error().Err(err).Msg("Image upload failed")
+				errorMsg := framework.Error(ctx.T("download.upload_failed", "Failed to upload the file, please try again later"))
+				job.Update(errorMsg)
 				return nil
 			}
 			return nil
@@ -312,10 +367,11 @@ func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 				}
 				filename = fmt.Sprintf("media_%d%s", time.Now().Unix(), ext)
 			}
-			err := uploader.UploadAndSendDocument(ctx.Context, ctx.MessageInfo.Chat, data, filename, caption)
+			err := uploader.UploadAndSendDocument(job.Context(), ctx.MessageInfo.Chat, data, filename, caption)
 			if err != nil {
-				errorMsg := framework.Error(fmt.Sprintf("Failed to upload/send document: %v", err))
-				ctx.Handler.EditMessage(ctx.MessageInfo, errorMsg)
+				ctx.Logger.Error().Err(err).Msg("Document upload failed")
+				errorMsg := framework.Error(ctx.T("download.upload_failed", "Failed to upload the file, please try again later"))
+				job.Update(errorMsg)
 				return nil
 			}
 			return nil
@@ -323,17 +379,58 @@ func (c *DownloadCommand) Execute(ctx *framework.Context) error {
 	}
 }
 
+// newYtdlp returns a yt-dlp command with the options every run shares.
+func newYtdlp() *ytdlp.Command {
+	return ytdlp.New().
+		NoPlaylist().          // Download only single video, not playlist
+		NoCheckCertificates(). // Skip certificate verification
+		// Use cookies if available, for sites that need a login
+		CookiesFromBrowser("firefox:/root/profile/").
+		AddHeaders(fmt.Sprintf("User-Agent:%s", os.Getenv("USER_AGENT")))
+}
+
+// ytdlpErrorMessage explains a failed yt-dlp run, pointing to the settings
+// that help with common failures.
+func ytdlpErrorMessage(ctx *framework.Context, url string, err error) string {
+	isYouTube := strings.Contains(url, "youtube.com") || strings.Contains(url, "youtu.be")
+	errStr := err.Error()
+
+	if isYouTube && (strings.Contains(errStr, "Sign in to confirm") || strings.Contains(errStr, "age")) {
+		return framework.Error(ctx.T("download.age_restricted",
+			"This video is age-restricted.\n\nTo download it, set YOUTUBE_VISITOR_DATA environment variable.\nSee README for instructions."))
+	} else if strings.Contains(errStr, "This content isn't available") {
+		return framework.Error(ctx.T("download.unavailable", "Content unavailable. Video may be private, deleted, or region-blocked."))
+	} else if !isYouTube && (strings.Contains(errStr, "login") || strings.Contains(errStr, "private") || strings.Contains(errStr, "authenticate")) {
+		return framework.Error(ctx.T("download.login_required",
+			"This content requires authentication.\n\nExport cookies from your browser and set COOKIES_PATH.\nSee README for instructions."))
+	}
+	return framework.Error(ctx.T("download.failed", "Download failed: %v", err))
+}
+
 func (c *DownloadCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
 		Name:        "download",
 		Aliases:     []string{"dl", "ytdl"},
 		Description: "Download media from various platforms",
 		Category:    "Utility",
-		Usage:       "/download <url>",
+		Usage:       "/download [--audio|--voice] [--quality=360|480|720|1080] [--doc] <url>",
+		Flags: []framework.Flag{
+			{Name: "audio", Short: "a", Type: framework.BoolParam, Description: "Send only the audio, as mp3"},
+			{Name: "voice", Short: "v", Type: framework.BoolParam, Description: "Send only the audio, as voice note"},
+			{Name: "quality", Short: "q", Type: framework.IntParam, Default: defaultQuality, Description: "Highest video resolution: 360, 480, 720 or 1080"},
+			{Name: "doc", Short: "d", Type: framework.BoolParam, Description: "Send the file as document"},
+		},
+		RateLimit: &framework.Limit{
+			Rate:  5,
+			Per:   10 * time.Minute,
+			Scope: framework.ScopeChat,
+		},
 		Examples: []string{
 			"/download https://www.youtube.com/watch?v=...",
 			"/dl https://www.instagram.com/p/...",
 			"/dl https://twitter.com/user/status/...",
+			"/dl --audio https://www.youtube.com/watch?v=...",
+			"/dl --quality=1080 --doc https://www.youtube.com/watch?v=...",
 		},
 	}
 }
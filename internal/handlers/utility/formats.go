@@ -0,0 +1,178 @@
+package utility
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/lrstanley/go-ytdlp"
+)
+
+// maxFormats bounds the formats listed by /formats; the best ones are kept.
+const maxFormats = 30
+
+type FormatsCommand struct{}
+
+func NewFormatsCommand() *FormatsCommand {
+	return &FormatsCommand{}
+}
+
+func (c *FormatsCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(
+			ctx.T("usage.line", "Usage: `%s`", c.Metadata().Usage)))
+	}
+	url := ctx.Args[0]
+
+	runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	// Only the info JSON is fetched, nothing is downloaded
+	result, err := newYtdlp().DumpSingleJSON().Run(runCtx, url)
+	if err != nil {
+		ctx.Logger.Warn().Err(err).Str("url", url).Msg("yt-dlp failed to list formats")
+		return ctx.Handler.SendResponse(ctx.MessageInfo, ytdlpErrorMessage(ctx, url, err))
+	}
+	infos, err := result.GetExtractedInfo()
+	if err != nil || len(infos) == 0 {
+		ctx.Logger.Warn().Err(err).Str("url", url).Msg("yt-dlp returned no info")
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(
+			ctx.T("formats.no_info", "Could not read the formats of this link")))
+	}
+	info := infos[0]
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("formats.title", "📋 Formats"))
+	title := deref(info.Title)
+	if info.Duration != nil {
+		title += fmt.Sprintf(" (%s)", time.Duration(*info.Duration*float64(time.Second)).Round(time.Second))
+	}
+	if title != "" {
+		builder.AddBold(title)
+	}
+
+	formats := listedFormats(info)
+	if len(formats) == 0 {
+		builder.AddLine(ctx.T("formats.none", "No formats are listed for this link; /download picks one itself."))
+		return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+	}
+
+	lines := make([]string, 0, len(formats))
+	for _, f := range formats {
+		lines = append(lines, describeFormat(f))
+	}
+	builder.AddCodeBlock(strings.Join(lines, "\n"))
+
+	if qualities := availableQualities(formats); len(qualities) > 0 {
+		builder.AddLine(ctx.T("formats.qualities", "Qualities for /download: %s", strings.Join(qualities, ", ")))
+	}
+	builder.AddEmptyLine().AddItalic(ctx.T("formats.hint", "Use /dl --quality=<height> <url>, or /dl --audio <url> for the audio only"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+// listedFormats returns the formats worth listing, best first. Storyboards
+// and other formats without audio or video are left out.
+func listedFormats(info *ytdlp.ExtractedInfo) []*ytdlp.ExtractedFormat {
+	var formats []*ytdlp.ExtractedFormat
+	for _, f := range info.Formats {
+		if f == nil || deref(f.Extension) == "mhtml" {
+			continue
+		}
+		// Storyboards have a height but neither codec; yt-dlp reports
+		// missing codecs as "none"
+		if noCodec(f.VCodec) && noCodec(f.ACodec) {
+			continue
+		}
+		formats = append(formats, f)
+	}
+	// yt-dlp lists formats worst first
+	slices.Reverse(formats)
+	if len(formats) > maxFormats {
+		formats = formats[:maxFormats]
+	}
+	return formats
+}
+
+// describeFormat returns one line such as "137 mp4 1920x1080 30fps avc1 / - 48.2MB".
+func describeFormat(f *ytdlp.ExtractedFormat) string {
+	parts := []string{deref(f.FormatID), deref(f.Extension)}
+
+	switch {
+	case f.Height == nil:
+		parts = append(parts, "audio")
+	case f.Resolution != nil:
+		parts = append(parts, *f.Resolution)
+	default:
+		parts = append(parts, fmt.Sprintf("%.0fp", *f.Height))
+	}
+	if f.FPS != nil && f.Height != nil {
+		parts = append(parts, fmt.Sprintf("%.0ffps", *f.FPS))
+	}
+	parts = append(parts, codecName(f.VCodec)+" / "+codecName(f.ACodec))
+
+	size := f.FileSize
+	if size == nil {
+		size = f.FileSizeApprox
+	}
+	if size != nil {
+		parts = append(parts, fmt.Sprintf("%.1fMB", float64(*size)/(1024*1024)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// availableQualities returns the --quality values that get a video of
+// exactly that height.
+func availableQualities(formats []*ytdlp.ExtractedFormat) []string {
+	var qualities []string
+	for _, quality := range downloadQualities {
+		for _, f := range formats {
+			if f.Height != nil && int(*f.Height) == quality {
+				qualities = append(qualities, fmt.Sprint(quality))
+				break
+			}
+		}
+	}
+	return qualities
+}
+
+// noCodec reports whether a format lacks codec.
+func noCodec(codec *string) bool {
+	return codec == nil || *codec == "" || *codec == "none"
+}
+
+// codecName shortens a codec such as "avc1.64001F" to "avc1"; missing
+// codecs are shown as "-".
+func codecName(codec *string) string {
+	if noCodec(codec) {
+		return "-"
+	}
+	name, _, _ := strings.Cut(*codec, ".")
+	return name
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (c *FormatsCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "formats",
+		Description: "List the formats and qualities a link can be downloaded in",
+		Category:    "Utility",
+		Usage:       "/formats <url>",
+		RateLimit: &framework.Limit{
+			Rate:  10,
+			Per:   10 * time.Minute,
+			Scope: framework.ScopeChat,
+		},
+		Examples: []string{
+			"/formats https://www.youtube.com/watch?v=...",
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package utility
+
+import (
+	"fmt"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+)
+
+type JobsCommand struct{}
+
+func NewJobsCommand() *JobsCommand {
+	return &JobsCommand{}
+}
+
+func (c *JobsCommand) Execute(ctx *framework.Context) error {
+	// Only the owner sees jobs from other chats
+	var jobs []*framework.Job
+	for _, job := range ctx.Jobs.List() {
+		if ctx.Role >= framework.RoleOwner || job.Chat == ctx.MessageInfo.Chat {
+			jobs = append(jobs, job)
+		}
+	}
+
+	if len(jobs) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Info(ctx.T("jobs.none", "No jobs are running")))
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("jobs.title", "⚙️ Running Jobs"))
+	for _, job := range jobs {
+		line := fmt.Sprintf("*#%s* /%s - %s", job.ID, job.Command, time.Since(job.StartedAt).Round(time.Second))
+		if ctx.Role >= framework.RoleOwner && job.Chat != ctx.MessageInfo.Chat {
+			line += " " + ctx.T("jobs.in_chat", "(in %s)", job.Chat)
+		}
+		builder.AddLine(line)
+		if progress := job.Progress(); progress != "" {
+			builder.AddLine(fmt.Sprintf("   _%s_", progress))
+		}
+	}
+	builder.AddEmptyLine().AddLine(ctx.T("jobs.hint", "Use `/cancel <id>` or reply `/cancel` to a job's status message"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *JobsCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "jobs",
+		Description: "List running downloads, image generations and other jobs",
+		Category:    "Utility",
+		Usage:       "/jobs",
+	}
+}
+
+type CancelCommand struct{}
+
+func NewCancelCommand() *CancelCommand {
+	return &CancelCommand{}
+}
+
+func (c *CancelCommand) Execute(ctx *framework.Context) error {
+	var job *framework.Job
+	var exists bool
+
+	if len(ctx.Args) > 0 {
+		job, exists = ctx.Jobs.Get(ctx.Args[0])
+		if !exists {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("cancel.unknown", "No running job %s", ctx.Args[0])))
+		}
+	} else {
+		quotedID := ctx.Message.GetExtendedTextMessage().GetContextInfo().GetStanzaID()
+		if quotedID == "" {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("cancel.usage", "Give a job ID (see /jobs) or reply to a job's status message")))
+		}
+		job, exists = ctx.Jobs.ByMessage(ctx.MessageInfo.Chat, quotedID)
+		if !exists {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("cancel.not_job", "The quoted message doesn't belong to a running job")))
+		}
+	}
+
+	if err := ctx.Jobs.Cancel(job, ctx.MessageInfo, ctx.Role); err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error()))
+	}
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("cancel.done", "Cancelled job #%s (/%s)", job.ID, job.Command)))
+}
+
+func (c *CancelCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "cancel",
+		Aliases:     []string{"stop"},
+		Description: "Cancel a running job you started (the owner can cancel any)",
+		Category:    "Utility",
+		Usage:       "/cancel [id]",
+		Essential:   true,
+		Examples: []string{
+			"/cancel 3",
+			"Reply to a download's status message with /cancel",
+		},
+	}
+}
@@ -0,0 +1,210 @@
+package utility
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxRemindersPerUser bounds the pending reminders of one sender.
+const maxRemindersPerUser = 50
+
+// Reminder is a pending reminder of the sender.
+type Reminder struct {
+	ID   int64
+	Text string
+	At   time.Time
+}
+
+// QuotedMessage is the message a reminder replies to when it's due.
+type QuotedMessage struct {
+	ID     types.MessageID
+	Sender types.JID
+	Text   string
+}
+
+// ReminderManager persists reminders. Reminders belong to the sender and
+// chat of the message that created them.
+type ReminderManager interface {
+	TimezoneResolver
+	AddReminder(msgInfo types.MessageInfo, at time.Time, text string, quote QuotedMessage) (int64, error)
+	// Reminders lists the sender's reminders in the chat of msgInfo, soonest
+	// first
+	Reminders(msgInfo types.MessageInfo) ([]Reminder, error)
+	// CancelReminder deletes one of the sender's reminders and reports
+	// whether it existed
+	CancelReminder(msgInfo types.MessageInfo, id int64) (bool, error)
+}
+
+type RemindMeCommand struct {
+	reminders ReminderManager
+}
+
+func NewRemindMeCommand(reminders ReminderManager) *RemindMeCommand {
+	return &RemindMeCommand{reminders: reminders}
+}
+
+func (c *RemindMeCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) > 0 {
+		switch strings.ToLower(ctx.Args[0]) {
+		case "list":
+			return c.list(ctx)
+		case "cancel":
+			return c.cancel(ctx)
+		}
+	}
+
+	usage := framework.Error(ctx.T("usage.line", "Usage: `%s`", c.Metadata().Usage))
+	loc := c.reminders.UserLocation(ctx.MessageInfo)
+	at, used, err := framework.ParseWhen(ctx.Args, time.Now().In(loc))
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error())+"\n"+usage)
+	}
+	text := ctx.RawRest(used)
+
+	// Replying to a message reminds of that message, otherwise the command
+	// itself is quoted
+	quote, isReply := quotedMessage(ctx.Message, ctx.MessageInfo.Chat)
+	if !isReply {
+		if text == "" {
+			return ctx.Handler.SendResponse(ctx.MessageInfo, usage)
+		}
+		quote = QuotedMessage{
+			ID:     ctx.MessageInfo.ID,
+			Sender: ctx.MessageInfo.Sender,
+			Text:   strings.TrimSpace("/" + ctx.Command + " " + ctx.RawArgs),
+		}
+	}
+
+	pending, err := c.reminders.Reminders(ctx.MessageInfo)
+	if err != nil {
+		return err
+	}
+	if len(pending) >= maxRemindersPerUser {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("remindme.too_many",
+			"You already have %d reminders here, cancel some first", len(pending))))
+	}
+
+	id, err := c.reminders.AddReminder(ctx.MessageInfo, at, text, quote)
+	if err != nil {
+		return fmt.Errorf("failed to save reminder: %w", err)
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(ctx.T("remindme.created",
+		"I'll remind you on %s (in %s) - reminder #%d", formatScheduleTime(at), formatUntil(time.Until(at)), id)))
+}
+
+func (c *RemindMeCommand) list(ctx *framework.Context) error {
+	reminders, err := c.reminders.Reminders(ctx.MessageInfo)
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("remindme.none", "You have no reminders in this chat")))
+	}
+
+	loc := c.reminders.UserLocation(ctx.MessageInfo)
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("remindme.title", "⏰ Your Reminders"))
+	for _, r := range reminders {
+		line := fmt.Sprintf("*#%d* %s", r.ID, formatScheduleTime(r.At.In(loc)))
+		if r.Text != "" {
+			line += " - " + preview(r.Text, 60)
+		}
+		builder.AddLine(line)
+	}
+	builder.AddEmptyLine().AddLine(ctx.T("remindme.hint", "Use `/remindme cancel <id>` to cancel one"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *RemindMeCommand) cancel(ctx *framework.Context) error {
+	if len(ctx.Args) < 2 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("usage.line", "Usage: `%s`", "/remindme cancel <id>")))
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(ctx.Args[1], "#"), 10, 64)
+	existed := false
+	if err == nil {
+		if existed, err = c.reminders.CancelReminder(ctx.MessageInfo, id); err != nil {
+			return err
+		}
+	}
+	if !existed {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("remindme.unknown", "You have no reminder #%s here", ctx.Args[1])))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("remindme.cancelled", "Cancelled reminder #%d", id)))
+}
+
+// quotedMessage returns the message msg replies to, if any. Quotes without
+// a participant are from the other side of a direct chat.
+func quotedMessage(msg *waProto.Message, chat types.JID) (QuotedMessage, bool) {
+	contextInfo := msg.GetExtendedTextMessage().GetContextInfo()
+	if contextInfo.GetStanzaID() == "" {
+		return QuotedMessage{}, false
+	}
+
+	sender := chat
+	if participant, err := types.ParseJID(contextInfo.GetParticipant()); err == nil && !participant.IsEmpty() {
+		sender = participant
+	}
+
+	quoted := contextInfo.GetQuotedMessage()
+	text := quoted.GetConversation()
+	for _, alt := range []string{
+		quoted.GetExtendedTextMessage().GetText(),
+		quoted.GetImageMessage().GetCaption(),
+		quoted.GetVideoMessage().GetCaption(),
+		quoted.GetDocumentMessage().GetCaption(),
+	} {
+		if text == "" {
+			text = alt
+		}
+	}
+
+	return QuotedMessage{ID: contextInfo.GetStanzaID(), Sender: sender, Text: text}, true
+}
+
+// formatUntil renders a duration coarsely, e.g. "2h30m" or "3d4h".
+func formatUntil(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "<1m"
+	}
+
+	var s string
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if n := d / unit.size; n > 0 {
+			s += strconv.Itoa(int(n)) + unit.name
+			d -= n * unit.size
+		}
+	}
+	return s
+}
+
+func (c *RemindMeCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "remindme",
+		Aliases:     []string{"remind"},
+		Description: "Get pinged in this chat later, quoting the message you replied to",
+		Category:    "Utility",
+		Usage:       "/remindme " + framework.WhenUsage + " [text] | list | cancel <id>",
+		Examples: []string{
+			"/remindme 30m Take the pizza out",
+			"/remindme tomorrow 9:00 Call the bank",
+			"Reply to a message with /remindme 3h",
+			"/remindme list",
+			"/remindme cancel 2",
+		},
+	}
+}
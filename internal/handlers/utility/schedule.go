@@ -0,0 +1,224 @@
+package utility
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxSchedulesPerChat keeps a chat from being flooded with scheduled
+// messages.
+const maxSchedulesPerChat = 25
+
+// ScheduledMessage is a message waiting to be sent by the scheduler.
+type ScheduledMessage struct {
+	ID      int64
+	Chat    types.JID
+	Creator string
+	Text    string
+	Lang    string
+	Repeat  string
+	NextRun time.Time
+}
+
+// MessageScheduler persists messages to be sent later. repeat is "",
+// "daily" or "weekly"; recurring messages keep the time of day in the
+// location of at.
+type MessageScheduler interface {
+	TimezoneResolver
+	ScheduleMessage(msgInfo types.MessageInfo, at time.Time, text, lang, repeat string) (int64, error)
+	// ScheduledMessages lists the messages of chat, or of all chats if chat
+	// is empty, soonest first
+	ScheduledMessages(chat types.JID) ([]ScheduledMessage, error)
+	ScheduledMessage(id int64) (ScheduledMessage, bool, error)
+	CancelSchedule(id int64) error
+}
+
+type ScheduleCommand struct {
+	scheduler MessageScheduler
+}
+
+func NewScheduleCommand(scheduler MessageScheduler) *ScheduleCommand {
+	return &ScheduleCommand{scheduler: scheduler}
+}
+
+func (c *ScheduleCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) > 0 {
+		switch strings.ToLower(ctx.Args[0]) {
+		case "list":
+			return c.list(ctx, len(ctx.Args) > 1 && strings.EqualFold(ctx.Args[1], "all"))
+		case "cancel":
+			return c.cancel(ctx)
+		}
+	}
+	return c.schedule(ctx)
+}
+
+func (c *ScheduleCommand) schedule(ctx *framework.Context) error {
+	usage := framework.Error(ctx.T("usage.line", "Usage: `%s`", c.Metadata().Usage))
+
+	at, used, err := framework.ParseWhen(ctx.Args, time.Now().In(c.scheduler.UserLocation(ctx.MessageInfo)))
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(err.Error())+"\n"+usage)
+	}
+	// The raw text keeps the message's line breaks and quotes
+	text := ctx.RawRest(used)
+	if text == "" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, usage)
+	}
+
+	lang := strings.ToLower(ctx.Flags.String("lang"))
+	if _, ok := constants.SupportedLanguages[lang]; lang != "" && !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("schedule.bad_lang", "Unsupported language `%s`", lang)))
+	}
+
+	repeat := strings.ToLower(ctx.Flags.String("repeat"))
+	if repeat != "" && repeat != "daily" && repeat != "weekly" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("schedule.bad_repeat", "--repeat must be daily or weekly")))
+	}
+
+	existing, err := c.scheduler.ScheduledMessages(ctx.MessageInfo.Chat)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= maxSchedulesPerChat {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("schedule.too_many",
+			"This chat already has %d scheduled messages, cancel some first", len(existing))))
+	}
+
+	id, err := c.scheduler.ScheduleMessage(ctx.MessageInfo, at, text, lang, repeat)
+	if err != nil {
+		return fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	response := ctx.T("schedule.created", "Scheduled #%d for %s", id, formatScheduleTime(at))
+	if repeat != "" {
+		response += " " + ctx.T("schedule.repeats", "(repeats %s)", repeat)
+	}
+	if lang != "" {
+		response += "\n" + ctx.T("schedule.translated", "It will be translated to %s when sent", constants.SupportedLanguages[lang])
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(response))
+}
+
+func (c *ScheduleCommand) list(ctx *framework.Context, all bool) error {
+	chat := ctx.MessageInfo.Chat
+	if all && ctx.Role >= framework.RoleOwner {
+		chat = types.EmptyJID
+	}
+
+	messages, err := c.scheduler.ScheduledMessages(chat)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Info(ctx.T("schedule.none", "No messages are scheduled")))
+	}
+
+	loc := c.scheduler.UserLocation(ctx.MessageInfo)
+	builder := framework.NewResponseBuilder().AddHeading(ctx.T("schedule.title", "⏰ Scheduled Messages"))
+	for _, m := range messages {
+		line := fmt.Sprintf("*#%d* %s", m.ID, formatScheduleTime(m.NextRun.In(loc)))
+		if m.Repeat != "" {
+			line += " 🔁 " + m.Repeat
+		}
+		if m.Lang != "" {
+			line += " → " + m.Lang
+		}
+		if chat.IsEmpty() {
+			line += " " + ctx.T("jobs.in_chat", "(in %s)", m.Chat)
+		}
+		builder.AddLine(line)
+		builder.AddLine("   _" + preview(m.Text, 60) + "_")
+	}
+	builder.AddEmptyLine().AddLine(ctx.T("schedule.hint", "Use `/schedule cancel <id>` to cancel one"))
+
+	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
+}
+
+func (c *ScheduleCommand) cancel(ctx *framework.Context) error {
+	if len(ctx.Args) < 2 {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("usage.line", "Usage: `%s`", "/schedule cancel <id>")))
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(ctx.Args[1], "#"), 10, 64)
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("schedule.unknown", "No scheduled message #%s", ctx.Args[1])))
+	}
+
+	m, exists, err := c.scheduler.ScheduledMessage(id)
+	if err != nil {
+		return err
+	}
+	// Schedules of other chats are only visible to the owner
+	if !exists || (m.Chat != ctx.MessageInfo.Chat.ToNonAD() && ctx.Role < framework.RoleOwner) {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("schedule.unknown", "No scheduled message #%s", ctx.Args[1])))
+	}
+	if !isCreator(ctx.MessageInfo, m.Creator) && ctx.Role < framework.RoleAdmin {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("schedule.not_yours", "Only its creator or an admin can cancel #%d", id)))
+	}
+
+	if err := c.scheduler.CancelSchedule(id); err != nil {
+		return fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo,
+		framework.Success(ctx.T("schedule.cancelled", "Cancelled scheduled message #%d", id)))
+}
+
+// isCreator reports whether the sender of msgInfo is creator, who is stored
+// by phone number where known.
+func isCreator(msgInfo types.MessageInfo, creator string) bool {
+	for _, jid := range []types.JID{msgInfo.Sender, msgInfo.SenderAlt} {
+		if !jid.IsEmpty() && jid.ToNonAD().String() == creator {
+			return true
+		}
+	}
+	return false
+}
+
+func formatScheduleTime(t time.Time) string {
+	return t.Format("Mon 2 Jan 15:04")
+}
+
+// preview shortens text to at most n runes for listings.
+func preview(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return text
+}
+
+func (c *ScheduleCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "schedule",
+		Description: "Send a message to this chat later, once or every day or week",
+		Category:    "Utility",
+		Usage:       "/schedule [--lang=<code>] [--repeat=daily|weekly] " + framework.WhenUsage + " <text> | list | cancel <id>",
+		MinRole:     framework.RoleTrusted,
+		Flags: []framework.Flag{
+			{Name: "lang", Short: "l", Type: framework.StringParam, Description: "Translate the text to this language when sending"},
+			{Name: "repeat", Short: "r", Type: framework.StringParam, Description: "Send it again every day or week"},
+		},
+		Examples: []string{
+			"/schedule 2h30m Don't forget the meeting",
+			"/schedule 18:30 Dinner is ready",
+			"/schedule --repeat=daily 9:00 Good morning everyone",
+			"/schedule --lang=hi tomorrow 10:00 The shop opens at noon",
+			"/schedule list",
+			"/schedule cancel 4",
+		},
+	}
+}
@@ -1,12 +1,8 @@
 package utility
 
 import (
-	"fmt"
-	"log"
-	"regexp"
-	"strings"
-
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // SedCommand represents the /sed command
@@ -17,123 +13,77 @@ func NewSedCommand() *SedCommand {
 	return &SedCommand{}
 }
 
-// Name returns the name of the command
-func (c *SedCommand) Name() string {
-	return "s"
-}
-
-// Description returns the description of the command
-func (c *SedCommand) Description() string {
-	return "Applies a sed-like substitution to a quoted message. Usage: s/pattern/replacement/flags or /s s/pattern/replacement/flags. Flags: g - global, i - ignore case, c - strikethrough original text"
-}
-
-// Execute executes the sed command
+// Execute applies the sed script to the quoted message. Quoting one of the
+// account's own messages from the account edits that message in place.
 func (c *SedCommand) Execute(ctx *framework.Context) error {
-	if ctx.Message.ExtendedTextMessage == nil || ctx.Message.ExtendedTextMessage.ContextInfo == nil || ctx.Message.ExtendedTextMessage.ContextInfo.QuotedMessage == nil {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, "Please quote a message to use the sed command.")
+	quote, ok := quotedMessage(ctx.Message, ctx.MessageInfo.Chat)
+	if !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			ctx.T("sed.no_quote", "Please quote a message to use the sed command."))
 	}
-
-	quotedText := ctx.Message.ExtendedTextMessage.ContextInfo.QuotedMessage.GetConversation()
-	if quotedText == "" {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, "Could not extract text from the quoted message.")
+	if quote.Text == "" {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			ctx.T("sed.no_text", "Could not extract text from the quoted message."))
 	}
-	log.Printf("Sed Command: Quoted text: %s", quotedText)
-
-	sedExpression := ctx.RawArgs
-	log.Printf("Sed Command: Expression: %s", sedExpression)
-	// Expected format: s/pattern/replacement/flags
-	// Using a simple regex to parse the components. This assumes '/' as delimiter.
-	// We need to escape the first '/' to match literally, and then capture everything between the next two '/'
-	// and then everything after the last '/'
-	// Regex: ^s/(.*?)/(.*?)/(.*)?$
-	// Group 1: pattern
-	// Group 2: replacement
-	// Group 3: flags (optional)
 
-	// Using a more robust regex that allows for escaping the delimiter if needed,
-	// but for simplicity, we'll stick to the basic '/' delimiter as per standard sed usage.
-	// A more robust parser would handle escaped delimiters within pattern/replacement.
-	// For this exercise, we'll assume the format s/pattern/replacement/flags
-	re := regexp.MustCompile(`^s/(.*?)/(.*?)/(.*)?$`)
-	matches := re.FindStringSubmatch(sedExpression)
-
-	if len(matches) < 3 {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, "Invalid sed expression. Usage: s/pattern/replacement/flags")
+	exprs, err := parseSedScript(ctx.RawArgs)
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(
+			ctx.T("sed.invalid", "Invalid sed expression: %v", err)+"\n"+
+				ctx.T("usage.line", "Usage: `%s`", c.Metadata().Usage)))
 	}
 
-	pattern := matches[1]
-	replacement := matches[2]
-	flags := matches[3]
-	log.Printf("Sed Command: Pattern: '%s', Replacement: '%s', Flags: '%s'", pattern, replacement, flags)
-
-	// Handle flags
-	reFlags := ""
-	global := false
-	crossOut := false
-	caseInsensitive := false
-
-	if strings.Contains(flags, "g") {
-		global = true
-	}
-	if strings.Contains(flags, "i") {
-		reFlags += "(?i)" // Add case-insensitive flag to regex
-		caseInsensitive = true
+	edited := quote.Text
+	for _, expr := range exprs {
+		edited = expr.apply(edited)
 	}
-	if strings.Contains(flags, "c") {
-		crossOut = true
-	}
-	log.Printf("Sed Command Flags: Global: %t, Case-Insensitive: %t, Cross-out: %t", global, caseInsensitive, crossOut)
+	ctx.Logger.Debug().Int("expressions", len(exprs)).Bool("changed", edited != quote.Text).Msg("Applied sed script")
 
-	// Compile the pattern regex
-	compiledPattern, err := regexp.Compile(reFlags + pattern)
-	if err != nil {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, fmt.Sprintf("Invalid regex pattern: %v", err))
+	if edited == quote.Text {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			ctx.T("sed.unchanged", "No changes were made. Pattern not found or expression invalid."))
 	}
-	log.Printf("Sed Command: Compiled Regex: %s", compiledPattern.String())
 
-	var editedText string
-	if global {
-		log.Println("Sed Command: Global replacement")
-		if crossOut {
-			log.Println("Sed Command: Cross-out enabled")
-			editedText = compiledPattern.ReplaceAllStringFunc(quotedText, func(match string) string {
-				return fmt.Sprintf("~%s~ %s", match, replacement)
-			})
-		} else {
-			editedText = compiledPattern.ReplaceAllString(quotedText, replacement)
+	if ctx.MessageInfo.IsFromMe && isOwnMessage(ctx.MessageInfo, quote.Sender) {
+		quoted := types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: ctx.MessageInfo.Chat, IsFromMe: true},
+			ID:            quote.ID,
 		}
-	} else {
-		log.Println("Sed Command: Single replacement")
-		// Replace only the first occurrence
-		firstMatchIndex := compiledPattern.FindStringIndex(quotedText)
-		if firstMatchIndex != nil {
-			matchedString := quotedText[firstMatchIndex[0]:firstMatchIndex[1]]
-			if crossOut {
-				log.Println("Sed Command: Cross-out enabled")
-				editedText = quotedText[:firstMatchIndex[0]] + fmt.Sprintf("~%s~ %s", matchedString, replacement) + quotedText[firstMatchIndex[1]:]
-			} else {
-				editedText = quotedText[:firstMatchIndex[0]] + compiledPattern.ReplaceAllString(matchedString, replacement) + quotedText[firstMatchIndex[1]:]
-			}
-		} else {
-			log.Println("Sed Command: No match found")
-			// No match found, text remains unchanged
-			editedText = quotedText
+		original := ctx.Message.GetExtendedTextMessage().GetContextInfo().GetQuotedMessage()
+		err := ctx.Handler.EditMessageWithOriginal(quoted, edited, original)
+		if err == nil {
+			return ctx.Handler.SendResponse(ctx.MessageInfo, "✏️ "+ctx.T("sed.edited", "Edited"))
 		}
+		// Messages can only be edited for a while; show the result instead
+		ctx.Logger.Warn().Err(err).Msg("Failed to edit quoted message")
 	}
-	log.Printf("Sed Command: Output: %s", editedText)
 
-	if editedText == quotedText {
-		return ctx.Handler.SendResponse(ctx.MessageInfo, "No changes were made. Pattern not found or expression invalid.")
-	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, edited)
+}
 
-	return ctx.Handler.SendResponse(ctx.MessageInfo, editedText)
+// isOwnMessage reports whether a message by sender was sent by the same
+// account as msgInfo, under its phone number or its LID.
+func isOwnMessage(msgInfo types.MessageInfo, sender types.JID) bool {
+	for _, own := range []types.JID{msgInfo.Sender, msgInfo.SenderAlt} {
+		if !own.IsEmpty() && own.User == sender.User && own.Server == sender.Server {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *SedCommand) Metadata() *framework.Metadata {
 	return &framework.Metadata{
 		Name:        "s",
-		Description: "Applies a sed-like substitution to a quoted message. Flags: g - global, i - ignore case, c - strikethrough original text",
+		Description: "Applies a sed-like substitution to a quoted message. Flags: g - global, i - ignore case, c - strikethrough original text, N - only the Nth match",
 		Category:    "Utility",
-		Usage:       "s/pattern/replacement/flags or /s s/pattern/replacement/flags",
+		Usage:       "s/pattern/replacement/flags[;s/.../.../...] or /s s/pattern/replacement/flags",
+		Examples: []string{
+			"s/teh/the/g",
+			`/s s|https://|http://|`,
+			`s/(\w+) (\w+)/\2 \1/`,
+			"s/a/b/2",
+			"s/colour/color/g; s/favourite/favorite/g",
+		},
 	}
 }
@@ -0,0 +1,225 @@
+package utility
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSedExpressions bounds the expressions of one sed script.
+const maxSedExpressions = 20
+
+// sedExpr is one parsed s command. template is the replacement in the
+// syntax of [regexp.Regexp.ExpandString]; occurrence is the first match
+// replaced, and global replaces every later one too.
+type sedExpr struct {
+	re         *regexp.Regexp
+	template   string
+	occurrence int
+	global     bool
+	crossOut   bool
+}
+
+// parseSedScript parses one or more s commands separated by ';' or line
+// breaks. Any punctuation can delimit a command and is escaped with a
+// backslash. In replacements & is the match, \1 to \9 are groups and \n a
+// line break. Flags are g (every match), i (ignore case), c (strike through
+// the match instead of removing it) and a number N (replace the Nth match,
+// with g the Nth and later ones).
+func parseSedScript(script string) ([]sedExpr, error) {
+	var exprs []sedExpr
+	rest := strings.TrimSpace(script)
+	for rest != "" {
+		if len(exprs) == maxSedExpressions {
+			return nil, fmt.Errorf("at most %d expressions are allowed", maxSedExpressions)
+		}
+
+		expr, remaining, err := parseSedExpr(rest)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		rest = strings.TrimLeftFunc(remaining, unicode.IsSpace)
+		if strings.HasPrefix(rest, ";") {
+			rest = strings.TrimLeftFunc(rest[1:], unicode.IsSpace)
+		} else if rest != "" && !strings.ContainsRune(remaining[:len(remaining)-len(rest)], '\n') {
+			return nil, fmt.Errorf("expected ; or a new line before %q", preview(rest, 10))
+		}
+	}
+	if len(exprs) == 0 {
+		return nil, fmt.Errorf("empty sed expression")
+	}
+	return exprs, nil
+}
+
+// parseSedExpr parses the s command at the start of s and returns what
+// follows it.
+func parseSedExpr(s string) (sedExpr, string, error) {
+	if !strings.HasPrefix(s, "s") {
+		return sedExpr{}, "", fmt.Errorf("expressions must look like s/pattern/replacement/flags")
+	}
+	delim, size := utf8.DecodeRuneInString(s[1:])
+	if size == 0 || delim == '\\' || delim == '\n' || unicode.IsSpace(delim) ||
+		unicode.IsLetter(delim) || unicode.IsDigit(delim) {
+		return sedExpr{}, "", fmt.Errorf("expressions must look like s/pattern/replacement/flags")
+	}
+	rest := s[1+size:]
+
+	pattern, rest, ok := cutSedPart(rest, delim)
+	if !ok {
+		return sedExpr{}, "", fmt.Errorf("missing %c after the pattern", delim)
+	}
+	replacement, rest, ok := cutSedPart(rest, delim)
+	if !ok {
+		return sedExpr{}, "", fmt.Errorf("missing %c after the replacement", delim)
+	}
+
+	expr := sedExpr{occurrence: 1}
+	var reFlags string
+	i := 0
+	for i < len(rest) && rest[i] != ';' && rest[i] != '\n' && rest[i] != ' ' && rest[i] != '\t' {
+		switch c := rest[i]; {
+		case c == 'g':
+			expr.global = true
+		case c == 'i' || c == 'I':
+			reFlags = "(?i)"
+		case c == 'c':
+			expr.crossOut = true
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(rest) && rest[j] >= '0' && rest[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(rest[i:j])
+			if err != nil || n < 1 {
+				return sedExpr{}, "", fmt.Errorf("invalid occurrence %q", rest[i:j])
+			}
+			expr.occurrence = n
+			i = j
+			continue
+		default:
+			return sedExpr{}, "", fmt.Errorf("unknown flag %q", c)
+		}
+		i++
+	}
+
+	re, err := regexp.Compile(reFlags + unescapeSedPattern(pattern, delim))
+	if err != nil {
+		return sedExpr{}, "", fmt.Errorf("invalid pattern: %v", err)
+	}
+	expr.re = re
+	expr.template = convertSedReplacement(replacement)
+	return expr, rest[i:], nil
+}
+
+// cutSedPart returns s up to the first delim not escaped by a backslash,
+// escapes included, and what follows the delimiter.
+func cutSedPart(s string, delim rune) (string, string, bool) {
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == delim:
+			return s[:i], s[i+utf8.RuneLen(r):], true
+		}
+	}
+	return "", "", false
+}
+
+// unescapeSedPattern turns an escaped delimiter into a literal one and keeps
+// every other escape for the regexp.
+func unescapeSedPattern(pattern string, delim rune) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped && r == delim:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if escaped {
+		b.WriteString(`\\`)
+	}
+	return b.String()
+}
+
+// convertSedReplacement translates a sed replacement into an ExpandString
+// template.
+func convertSedReplacement(replacement string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range replacement {
+		switch {
+		case escaped:
+			escaped = false
+			switch {
+			case r >= '1' && r <= '9':
+				b.WriteString("${" + string(r) + "}")
+			case r == 'n':
+				b.WriteByte('\n')
+			case r == 't':
+				b.WriteByte('\t')
+			case r == '$':
+				b.WriteString("$$")
+			default:
+				// \&, \\ and the escaped delimiter stand for themselves
+				b.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case r == '&':
+			b.WriteString("${0}")
+		case r == '$':
+			b.WriteString("$$")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if escaped {
+		b.WriteByte('\\')
+	}
+	return b.String()
+}
+
+// apply runs the expression on text.
+func (e sedExpr) apply(text string) string {
+	matches := e.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for n, match := range matches {
+		occurrence := n + 1
+		if occurrence < e.occurrence || (occurrence > e.occurrence && !e.global) {
+			continue
+		}
+
+		b.WriteString(text[last:match[0]])
+		expanded := string(e.re.ExpandString(nil, e.template, text, match))
+		if e.crossOut && match[0] < match[1] {
+			b.WriteString("~" + text[match[0]:match[1]] + "~ ")
+		}
+		b.WriteString(expanded)
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package utility
+
+import "testing"
+
+func TestSedScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		text   string
+		want   string
+	}{
+		{"first match", "s/a/b/", "banana", "bbnana"},
+		{"global", "s/a/b/g", "banana", "bbnbnb"},
+		{"ignore case", "s/hello/bye/i", "HELLO world", "bye world"},
+		{"nth match", "s/a/b/2", "banana", "banbna"},
+		{"nth and later matches", "s/a/b/2g", "banana", "banbnb"},
+		{"global before number", "s/a/b/g2", "banana", "banbnb"},
+		{"whole match", "s/[0-9]+/<&>/g", "1 and 22", "<1> and <22>"},
+		{"escaped ampersand", `s/and/\&/`, "salt and pepper", "salt & pepper"},
+		{"groups", `s/(\w+) (\w+)/\2 \1/`, "hello world", "world hello"},
+		{"line break", `s/, /\n/g`, "a, b", "a\nb"},
+		{"dollar is literal", "s/cost/$1/", "cost", "$1"},
+		{"escaped delimiter in pattern", `s/a\/b/x/`, "a/b", "x"},
+		{"escaped delimiter in replacement", `s/x/a\/b/`, "x", "a/b"},
+		{"other delimiter", "s|https://|http://|", "https://example.com", "http://example.com"},
+		{"escaped other delimiter", `s|a\|b|c|`, "a|b", "c"},
+		{"delimiter that is a regexp operator", `s.a\.b.x.`, "a.b", "x"},
+		{"cross out", "s/teh/the/c", "teh cat", "~teh~ the cat"},
+		{"chain with semicolons", "s/a/b/g; s/b/c/", "aa", "cb"},
+		{"chain with line breaks", "s/a/b/\ns|b|c|g", "ab", "cc"},
+		{"chain with mixed delimiters", "s/x/y/;s,y,z,", "x", "z"},
+		{"no match", "s/q/z/", "banana", "banana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprs, err := parseSedScript(tt.script)
+			if err != nil {
+				t.Fatalf("parseSedScript(%q) failed: %v", tt.script, err)
+			}
+			got := tt.text
+			for _, expr := range exprs {
+				got = expr.apply(got)
+			}
+			if got != tt.want {
+				t.Errorf("%q on %q = %q, want %q", tt.script, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSedScriptErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"empty", "  "},
+		{"not an s command", "y/a/b/"},
+		{"letter delimiter", "sxaxbx"},
+		{"missing delimiter after pattern", "s/a"},
+		{"missing delimiter after replacement", "s/a/b"},
+		{"unknown flag", "s/a/b/z"},
+		{"zero occurrence", "s/a/b/0"},
+		{"invalid pattern", "s/(/b/"},
+		{"missing separator", "s/a/b/ s/c/d/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseSedScript(tt.script); err == nil {
+				t.Errorf("parseSedScript(%q) succeeded, want an error", tt.script)
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ func NewSupportedLangsCommand() *SupportedLangsCommand {
 
 func (c *SupportedLangsCommand) Execute(ctx *framework.Context) error {
 	builder := framework.NewResponseBuilder()
-	builder.AddHeading("Supported Languages")
+	builder.AddHeading(ctx.T("langs.title", "Supported Languages"))
 
 	// Create sorted list of languages
 	type langInfo struct {
@@ -42,7 +42,7 @@ func (c *SupportedLangsCommand) Execute(ctx *framework.Context) error {
 
 	builder.AddList(langList...)
 	builder.AddEmptyLine()
-	builder.AddItalic("Use any language code above to translate text to that language")
+	builder.AddItalic(ctx.T("langs.hint", "Use any language code above to translate text to that language"))
 
 	return ctx.Handler.SendResponse(ctx.MessageInfo, builder.Build())
 }
@@ -0,0 +1,87 @@
+package utility
+
+import (
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// TimezoneResolver returns the timezone times given by the sender of
+// msgInfo are in.
+type TimezoneResolver interface {
+	UserLocation(msgInfo types.MessageInfo) *time.Location
+}
+
+// TimezoneManager stores the timezone of each sender by IANA name.
+type TimezoneManager interface {
+	TimezoneResolver
+	UserTimezone(msgInfo types.MessageInfo) (string, error)
+	SetUserTimezone(msgInfo types.MessageInfo, name string) error
+}
+
+type TimezoneCommand struct {
+	zones TimezoneManager
+}
+
+func NewTimezoneCommand(zones TimezoneManager) *TimezoneCommand {
+	return &TimezoneCommand{zones: zones}
+}
+
+func (c *TimezoneCommand) Execute(ctx *framework.Context) error {
+	if len(ctx.Args) == 0 {
+		name, err := c.zones.UserTimezone(ctx.MessageInfo)
+		if err != nil {
+			return err
+		}
+		loc := c.zones.UserLocation(ctx.MessageInfo)
+
+		line := ctx.T("timezone.current", "Your timezone is %s, where it is now %s", loc, formatScheduleTime(time.Now().In(loc)))
+		if name == "" {
+			line = ctx.T("timezone.default", "You use the server's timezone %s, where it is now %s", loc, formatScheduleTime(time.Now().In(loc)))
+		}
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.NewResponseBuilder().
+			AddHeading(ctx.T("timezone.title", "🕒 Timezone")).
+			AddLine(line).
+			AddEmptyLine().
+			AddLine(ctx.T("timezone.hint", "Send `/timezone <name>` to change it, e.g. `/timezone Asia/Kolkata`")).
+			Build())
+	}
+
+	name := ctx.Args[0]
+	if strings.EqualFold(name, "reset") {
+		if err := c.zones.SetUserTimezone(ctx.MessageInfo, ""); err != nil {
+			return err
+		}
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(ctx.T("timezone.reset", "You use the server's timezone again")))
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" || strings.EqualFold(name, "local") {
+		return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Error(ctx.T("timezone.unknown",
+			"Unknown timezone `%s`, use a name like `Asia/Kolkata`, `Europe/Moscow` or `UTC`", name)))
+	}
+
+	if err := c.zones.SetUserTimezone(ctx.MessageInfo, loc.String()); err != nil {
+		return err
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.Success(ctx.T("timezone.set",
+		"Your timezone is now %s, where it is %s", loc, formatScheduleTime(time.Now().In(loc)))))
+}
+
+func (c *TimezoneCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "timezone",
+		Aliases:     []string{"tz"},
+		Description: "Set the timezone your reminders and schedules use",
+		Category:    "Utility",
+		Usage:       "/timezone [name|reset]",
+		Examples: []string{
+			"/timezone",
+			"/timezone Asia/Kolkata",
+			"/timezone reset",
+		},
+	}
+}
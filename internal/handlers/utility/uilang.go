@@ -0,0 +1,135 @@
+package utility
+
+import (
+	"strings"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// UILanguageManager stores the language the bot replies in, chosen per
+// sender or per chat.
+type UILanguageManager interface {
+	UILanguage(msgInfo types.MessageInfo) (lang, source string, err error)
+	SetSenderUILanguage(msgInfo types.MessageInfo, lang string) error
+	SetChatUILanguage(chat types.JID, lang string) error
+}
+
+type UILangCommand struct {
+	manager UILanguageManager
+}
+
+func NewUILangCommand(manager UILanguageManager) *UILangCommand {
+	return &UILangCommand{manager: manager}
+}
+
+func (c *UILangCommand) Execute(ctx *framework.Context) error {
+	args := ctx.Args
+	if len(args) == 0 {
+		return c.show(ctx)
+	}
+
+	forChat := strings.EqualFold(args[0], "chat")
+	if forChat {
+		if ctx.Role < framework.RoleAdmin {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("error.role_required", "This command requires %s permissions", framework.RoleAdmin)))
+		}
+		args = args[1:]
+		if len(args) == 0 {
+			return ctx.Handler.SendResponse(ctx.MessageInfo,
+				framework.Error(ctx.T("uilang.usage", "Usage: `%s`", c.Metadata().Usage)))
+		}
+	}
+
+	lang := strings.ToLower(args[0])
+	if lang == "reset" {
+		lang = ""
+	} else if _, ok := constants.SupportedLanguages[lang]; !ok {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("uilang.unsupported", "Unsupported language `%s`, use one of %s", lang, languageCodes())))
+	}
+
+	var err error
+	if forChat {
+		err = c.manager.SetChatUILanguage(ctx.MessageInfo.Chat, lang)
+	} else {
+		err = c.manager.SetSenderUILanguage(ctx.MessageInfo, lang)
+	}
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("uilang.failed", "Failed to save the language: %v", err)))
+	}
+
+	// Confirm in the new language
+	current, _, _ := c.manager.UILanguage(ctx.MessageInfo)
+	locale := ctx.Locale.In(current)
+	switch {
+	case lang == "" && forChat:
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(locale.T("uilang.chat_reset", "This chat uses the default language again")))
+	case lang == "":
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(locale.T("uilang.user_reset", "Your language choice was cleared")))
+	case forChat:
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(locale.T("uilang.chat_set", "Replies in this chat are now in %s", i18n.LanguageName(lang))))
+	default:
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Success(locale.T("uilang.user_set", "Replies to you are now in %s", i18n.LanguageName(lang))))
+	}
+}
+
+func (c *UILangCommand) show(ctx *framework.Context) error {
+	lang, source, err := c.manager.UILanguage(ctx.MessageInfo)
+	if err != nil {
+		return ctx.Handler.SendResponse(ctx.MessageInfo,
+			framework.Error(ctx.T("uilang.failed_load", "Failed to load the language: %v", err)))
+	}
+
+	var line string
+	switch source {
+	case "user":
+		line = ctx.T("uilang.current_user", "You chose %s", i18n.LanguageName(lang))
+	case "chat":
+		line = ctx.T("uilang.current_chat", "This chat uses %s", i18n.LanguageName(lang))
+	default:
+		line = ctx.T("uilang.current_default", "The default language is %s", i18n.LanguageName(lang))
+	}
+	return ctx.Handler.SendResponse(ctx.MessageInfo, framework.NewResponseBuilder().
+		AddHeading(ctx.T("uilang.title", "🌐 Bot Language")).
+		AddLine(line).
+		AddEmptyLine().
+		AddLine(ctx.T("uilang.hint", "Send `/uilang <code>` to change it (%s)", languageCodes())).
+		Build())
+}
+
+// languageCodes lists the supported codes as "`en`, `hi`, ...".
+func languageCodes() string {
+	codes := make([]string, 0, len(constants.SupportedLanguages))
+	for _, code := range i18n.Codes() {
+		if _, ok := constants.SupportedLanguages[code]; ok {
+			codes = append(codes, "`"+code+"`")
+		}
+	}
+	return strings.Join(codes, ", ")
+}
+
+func (c *UILangCommand) Metadata() *framework.Metadata {
+	return &framework.Metadata{
+		Name:        "uilang",
+		Aliases:     []string{"botlang"},
+		Description: "Choose the language the bot replies in",
+		Category:    "Utility",
+		Usage:       "/uilang [chat] [code|reset]",
+		Essential:   true,
+		Examples: []string{
+			"/uilang",
+			"/uilang hi",
+			"/uilang chat pa",
+			"/uilang reset",
+		},
+	}
+}
@@ -0,0 +1,258 @@
+package i18n
+
+import "sort"
+
+// languageNames holds the languages with a built-in catalog, by their own
+// name.
+var languageNames = map[string]string{
+	"en": "English",
+	"hi": "हिन्दी",
+	"pa": "ਪੰਜਾਬੀ",
+	"ru": "Русский",
+}
+
+// LanguageName returns the native name of lang, or lang itself if it has no
+// built-in catalog.
+func LanguageName(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// Codes lists the languages with a built-in catalog, sorted.
+func Codes() []string {
+	codes := make([]string, 0, len(languageNames))
+	for code := range languageNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// catalogs holds the built-in translations by language and key. The fmt
+// verbs of every entry must match the English text at its call site.
+var catalogs = map[string]map[string]string{
+	"hi": {
+		"template.InvalidCommand":      "❌ अज्ञात कमांड: `/%s`\nउपलब्ध कमांड के लिए `/help` लिखें।",
+		"template.MissingParameter":    "❌ ज़रूरी पैरामीटर नहीं दिया गया: %s\nउपयोग: `%s`",
+		"template.InvalidParameter":    "❌ अमान्य %s: %s\nअपेक्षित: %s",
+		"template.PermissionDenied":    "❌ अनुमति नहीं है: %s",
+		"template.RateLimited":         "⏱️ थोड़ा धीरे! आप यह कमांड %s बाद फिर से इस्तेमाल कर सकते हैं।",
+		"template.InternalError":       "❌ एक आंतरिक त्रुटि हुई। कृपया बाद में फिर कोशिश करें।",
+		"template.FeatureNotAvailable": "🚧 यह सुविधा अभी उपलब्ध नहीं है।",
+
+		"error.internal":      "कमांड चलाते समय कुछ गड़बड़ हो गई।",
+		"error.timeout":       "कमांड में बहुत ज़्यादा समय लगा और उसे रोक दिया गया।",
+		"error.failed":        "कमांड नहीं चल सका। कृपया बाद में फिर कोशिश करें।",
+		"error.role_required": "इस कमांड के लिए %s अनुमति चाहिए",
+
+		"job.panic":     "जॉब चलाते समय कुछ गड़बड़ हो गई",
+		"job.failed":    "/%s विफल रहा",
+		"job.cancelled": "/%s रद्द कर दिया गया",
+		"job.timeout":   "/%s में बहुत ज़्यादा समय लगा और उसे रोक दिया गया",
+
+		"suggest.did_you_mean": "क्या आपका मतलब %s था?",
+		"list.or":              "या",
+		"usage.line":           "उपयोग: `%s`",
+		"dispatch.busy":        "इस चैट में बहुत सारे कमांड इंतज़ार में हैं, कृपया थोड़ी देर बाद फिर कोशिश करें",
+
+		"afk.reply":    "आप जिनसे संपर्क करना चाहते हैं वे अभी उपलब्ध नहीं हैं, ज़रूरी हो तो कॉल करें।",
+		"afk.disabled": "❌ AFK मोड बंद है।",
+
+		"jobs.none":      "कोई जॉब नहीं चल रहा है",
+		"jobs.title":     "⚙️ चल रहे जॉब",
+		"jobs.in_chat":   "(%s में)",
+		"jobs.hint":      "`/cancel <id>` भेजें या किसी जॉब के स्टेटस संदेश पर `/cancel` से जवाब दें",
+		"cancel.unknown": "%s नाम का कोई जॉब नहीं चल रहा है",
+		"cancel.usage":   "जॉब ID दें (/jobs देखें) या किसी जॉब के स्टेटस संदेश पर जवाब दें",
+		"cancel.not_job": "उद्धृत संदेश किसी चल रहे जॉब का नहीं है",
+		"cancel.done":    "जॉब #%s (/%s) रद्द कर दिया गया",
+
+		"help.title":           "📋 उपलब्ध कमांड",
+		"help.hint":            "विवरण के लिए `/help <command>`, किसी श्रेणी के लिए `/help <category>` या खोजने के लिए `/help search <term>` भेजें",
+		"help.no_match":        "%q से कोई कमांड मेल नहीं खाता",
+		"help.search_title":    "🔎 \"%s\" से मेल खाते कमांड",
+		"help.category_empty":  "आप यहाँ %s का कोई कमांड इस्तेमाल नहीं कर सकते",
+		"help.unknown":         "`%s` नाम का कोई कमांड या श्रेणी नहीं है",
+		"help.page":            "पेज %d / %d",
+		"help.next_page":       "अगले पेज के लिए `%s %d` भेजें",
+		"help.command":         "*कमांड:* *%s*",
+		"help.aliases":         "*उपनाम:* %s",
+		"help.description":     "*विवरण:* %s",
+		"help.usage":           "*उपयोग:* `%s`",
+		"help.parameters":      "*पैरामीटर:*",
+		"help.required":        "*(ज़रूरी)*",
+		"help.flags":           "*फ़्लैग:*",
+		"help.default":         "(डिफ़ॉल्ट: %v)",
+		"help.examples":        "*उदाहरण:*",
+		"help.rate_limit":      "*रेट लिमिट:* %s",
+		"help.requires_role":   "*इस कमांड के लिए %s अनुमति चाहिए*",
+		"help.subcommands":     "*सबकमांड:*",
+		"help.subcommand_hint": "विवरण के लिए `/help %s <subcommand>` भेजें",
+		"help.other_commands":  "अन्य कमांड",
+		"owner.required":       "यह कमांड केवल मालिक के लिए है",
+
+		"uilang.title":           "🌐 बॉट की भाषा",
+		"uilang.usage":           "उपयोग: `%s`",
+		"uilang.unsupported":     "भाषा `%s` समर्थित नहीं है, इनमें से एक चुनें: %s",
+		"uilang.failed":          "भाषा सहेजी नहीं जा सकी: %v",
+		"uilang.failed_load":     "भाषा लोड नहीं हो सकी: %v",
+		"uilang.current_user":    "आपने %s चुनी है",
+		"uilang.current_chat":    "यह चैट %s इस्तेमाल करती है",
+		"uilang.current_default": "डिफ़ॉल्ट भाषा %s है",
+		"uilang.hint":            "बदलने के लिए `/uilang <code>` भेजें (%s)",
+		"uilang.user_set":        "अब आपको जवाब %s में मिलेंगे",
+		"uilang.chat_set":        "इस चैट में अब जवाब %s में होंगे",
+		"uilang.user_reset":      "आपकी चुनी हुई भाषा हटा दी गई",
+		"uilang.chat_reset":      "यह चैट फिर से डिफ़ॉल्ट भाषा इस्तेमाल करती है",
+	},
+	"pa": {
+		"template.InvalidCommand":      "❌ ਅਣਜਾਣ ਕਮਾਂਡ: `/%s`\nਉਪਲਬਧ ਕਮਾਂਡਾਂ ਲਈ `/help` ਲਿਖੋ।",
+		"template.MissingParameter":    "❌ ਲੋੜੀਂਦਾ ਪੈਰਾਮੀਟਰ ਨਹੀਂ ਦਿੱਤਾ: %s\nਵਰਤੋਂ: `%s`",
+		"template.InvalidParameter":    "❌ ਗਲਤ %s: %s\nਉਮੀਦ: %s",
+		"template.PermissionDenied":    "❌ ਇਜਾਜ਼ਤ ਨਹੀਂ: %s",
+		"template.RateLimited":         "⏱️ ਥੋੜ੍ਹਾ ਹੌਲੀ! ਤੁਸੀਂ ਇਹ ਕਮਾਂਡ %s ਬਾਅਦ ਦੁਬਾਰਾ ਵਰਤ ਸਕਦੇ ਹੋ।",
+		"template.InternalError":       "❌ ਇੱਕ ਅੰਦਰੂਨੀ ਗਲਤੀ ਹੋਈ। ਕਿਰਪਾ ਕਰਕੇ ਬਾਅਦ ਵਿੱਚ ਦੁਬਾਰਾ ਕੋਸ਼ਿਸ਼ ਕਰੋ।",
+		"template.FeatureNotAvailable": "🚧 ਇਹ ਸੁਵਿਧਾ ਇਸ ਵੇਲੇ ਉਪਲਬਧ ਨਹੀਂ ਹੈ।",
+
+		"error.internal":      "ਕਮਾਂਡ ਚਲਾਉਂਦੇ ਸਮੇਂ ਕੁਝ ਗਲਤ ਹੋ ਗਿਆ।",
+		"error.timeout":       "ਕਮਾਂਡ ਨੂੰ ਬਹੁਤ ਸਮਾਂ ਲੱਗਿਆ ਅਤੇ ਉਸਨੂੰ ਰੋਕ ਦਿੱਤਾ ਗਿਆ।",
+		"error.failed":        "ਕਮਾਂਡ ਨਹੀਂ ਚੱਲ ਸਕੀ। ਕਿਰਪਾ ਕਰਕੇ ਬਾਅਦ ਵਿੱਚ ਫਿਰ ਕੋਸ਼ਿਸ਼ ਕਰੋ।",
+		"error.role_required": "ਇਸ ਕਮਾਂਡ ਲਈ %s ਇਜਾਜ਼ਤ ਚਾਹੀਦੀ ਹੈ",
+
+		"job.panic":     "ਜੌਬ ਚਲਾਉਂਦੇ ਸਮੇਂ ਕੁਝ ਗਲਤ ਹੋ ਗਿਆ",
+		"job.failed":    "/%s ਅਸਫਲ ਰਿਹਾ",
+		"job.cancelled": "/%s ਰੱਦ ਕਰ ਦਿੱਤਾ ਗਿਆ",
+		"job.timeout":   "/%s ਨੂੰ ਬਹੁਤ ਸਮਾਂ ਲੱਗਿਆ ਅਤੇ ਉਸਨੂੰ ਰੋਕ ਦਿੱਤਾ ਗਿਆ",
+
+		"suggest.did_you_mean": "ਕੀ ਤੁਹਾਡਾ ਮਤਲਬ %s ਸੀ?",
+		"list.or":              "ਜਾਂ",
+		"usage.line":           "ਵਰਤੋਂ: `%s`",
+		"dispatch.busy":        "ਇਸ ਚੈਟ ਵਿੱਚ ਬਹੁਤ ਸਾਰੀਆਂ ਕਮਾਂਡਾਂ ਉਡੀਕ ਵਿੱਚ ਹਨ, ਕਿਰਪਾ ਕਰਕੇ ਥੋੜ੍ਹੀ ਦੇਰ ਬਾਅਦ ਕੋਸ਼ਿਸ਼ ਕਰੋ",
+
+		"afk.reply":    "ਤੁਸੀਂ ਜਿਨ੍ਹਾਂ ਨਾਲ ਸੰਪਰਕ ਕਰਨਾ ਚਾਹੁੰਦੇ ਹੋ ਉਹ ਇਸ ਵੇਲੇ ਉਪਲਬਧ ਨਹੀਂ ਹਨ, ਜ਼ਰੂਰੀ ਹੋਵੇ ਤਾਂ ਕਾਲ ਕਰੋ।",
+		"afk.disabled": "❌ AFK ਮੋਡ ਬੰਦ ਹੈ।",
+
+		"jobs.none":      "ਕੋਈ ਜੌਬ ਨਹੀਂ ਚੱਲ ਰਿਹਾ",
+		"jobs.title":     "⚙️ ਚੱਲ ਰਹੇ ਜੌਬ",
+		"jobs.in_chat":   "(%s ਵਿੱਚ)",
+		"jobs.hint":      "`/cancel <id>` ਭੇਜੋ ਜਾਂ ਕਿਸੇ ਜੌਬ ਦੇ ਸਟੇਟਸ ਸੁਨੇਹੇ ਨੂੰ `/cancel` ਨਾਲ ਜਵਾਬ ਦਿਓ",
+		"cancel.unknown": "%s ਨਾਂ ਦਾ ਕੋਈ ਜੌਬ ਨਹੀਂ ਚੱਲ ਰਿਹਾ",
+		"cancel.usage":   "ਜੌਬ ID ਦਿਓ (/jobs ਵੇਖੋ) ਜਾਂ ਕਿਸੇ ਜੌਬ ਦੇ ਸਟੇਟਸ ਸੁਨੇਹੇ ਨੂੰ ਜਵਾਬ ਦਿਓ",
+		"cancel.not_job": "ਹਵਾਲਾ ਦਿੱਤਾ ਸੁਨੇਹਾ ਕਿਸੇ ਚੱਲ ਰਹੇ ਜੌਬ ਦਾ ਨਹੀਂ ਹੈ",
+		"cancel.done":    "ਜੌਬ #%s (/%s) ਰੱਦ ਕਰ ਦਿੱਤਾ ਗਿਆ",
+
+		"help.title":           "📋 ਉਪਲਬਧ ਕਮਾਂਡਾਂ",
+		"help.hint":            "ਵੇਰਵੇ ਲਈ `/help <command>`, ਕਿਸੇ ਸ਼੍ਰੇਣੀ ਲਈ `/help <category>` ਜਾਂ ਖੋਜ ਲਈ `/help search <term>` ਭੇਜੋ",
+		"help.no_match":        "%q ਨਾਲ ਕੋਈ ਕਮਾਂਡ ਮੇਲ ਨਹੀਂ ਖਾਂਦੀ",
+		"help.search_title":    "🔎 \"%s\" ਨਾਲ ਮੇਲ ਖਾਂਦੀਆਂ ਕਮਾਂਡਾਂ",
+		"help.category_empty":  "ਤੁਸੀਂ ਇੱਥੇ %s ਦੀ ਕੋਈ ਕਮਾਂਡ ਨਹੀਂ ਵਰਤ ਸਕਦੇ",
+		"help.unknown":         "`%s` ਨਾਂ ਦੀ ਕੋਈ ਕਮਾਂਡ ਜਾਂ ਸ਼੍ਰੇਣੀ ਨਹੀਂ ਹੈ",
+		"help.page":            "ਪੰਨਾ %d / %d",
+		"help.next_page":       "ਅਗਲੇ ਪੰਨੇ ਲਈ `%s %d` ਭੇਜੋ",
+		"help.command":         "*ਕਮਾਂਡ:* *%s*",
+		"help.aliases":         "*ਉਪਨਾਮ:* %s",
+		"help.description":     "*ਵੇਰਵਾ:* %s",
+		"help.usage":           "*ਵਰਤੋਂ:* `%s`",
+		"help.parameters":      "*ਪੈਰਾਮੀਟਰ:*",
+		"help.required":        "*(ਲੋੜੀਂਦਾ)*",
+		"help.flags":           "*ਫਲੈਗ:*",
+		"help.default":         "(ਡਿਫਾਲਟ: %v)",
+		"help.examples":        "*ਉਦਾਹਰਨਾਂ:*",
+		"help.rate_limit":      "*ਰੇਟ ਲਿਮਿਟ:* %s",
+		"help.requires_role":   "*ਇਸ ਕਮਾਂਡ ਲਈ %s ਇਜਾਜ਼ਤ ਚਾਹੀਦੀ ਹੈ*",
+		"help.subcommands":     "*ਸਬਕਮਾਂਡਾਂ:*",
+		"help.subcommand_hint": "ਵੇਰਵੇ ਲਈ `/help %s <subcommand>` ਭੇਜੋ",
+		"help.other_commands":  "ਹੋਰ ਕਮਾਂਡਾਂ",
+		"owner.required":       "ਇਹ ਕਮਾਂਡ ਸਿਰਫ਼ ਮਾਲਕ ਲਈ ਹੈ",
+
+		"uilang.title":           "🌐 ਬੋਟ ਦੀ ਭਾਸ਼ਾ",
+		"uilang.usage":           "ਵਰਤੋਂ: `%s`",
+		"uilang.unsupported":     "ਭਾਸ਼ਾ `%s` ਸਮਰਥਿਤ ਨਹੀਂ ਹੈ, ਇਹਨਾਂ ਵਿੱਚੋਂ ਇੱਕ ਚੁਣੋ: %s",
+		"uilang.failed":          "ਭਾਸ਼ਾ ਸੰਭਾਲੀ ਨਹੀਂ ਜਾ ਸਕੀ: %v",
+		"uilang.failed_load":     "ਭਾਸ਼ਾ ਲੋਡ ਨਹੀਂ ਹੋ ਸਕੀ: %v",
+		"uilang.current_user":    "ਤੁਸੀਂ %s ਚੁਣੀ ਹੈ",
+		"uilang.current_chat":    "ਇਹ ਚੈਟ %s ਵਰਤਦੀ ਹੈ",
+		"uilang.current_default": "ਡਿਫ਼ੌਲਟ ਭਾਸ਼ਾ %s ਹੈ",
+		"uilang.hint":            "ਬਦਲਣ ਲਈ `/uilang <code>` ਭੇਜੋ (%s)",
+		"uilang.user_set":        "ਹੁਣ ਤੁਹਾਨੂੰ ਜਵਾਬ %s ਵਿੱਚ ਮਿਲਣਗੇ",
+		"uilang.chat_set":        "ਇਸ ਚੈਟ ਵਿੱਚ ਹੁਣ ਜਵਾਬ %s ਵਿੱਚ ਹੋਣਗੇ",
+		"uilang.user_reset":      "ਤੁਹਾਡੀ ਚੁਣੀ ਭਾਸ਼ਾ ਹਟਾ ਦਿੱਤੀ ਗਈ",
+		"uilang.chat_reset":      "ਇਹ ਚੈਟ ਮੁੜ ਡਿਫ਼ੌਲਟ ਭਾਸ਼ਾ ਵਰਤਦੀ ਹੈ",
+	},
+	"ru": {
+		"template.InvalidCommand":      "❌ Неизвестная команда: `/%s`\nНапишите `/help`, чтобы увидеть доступные команды.",
+		"template.MissingParameter":    "❌ Не указан обязательный параметр: %s\nИспользование: `%s`",
+		"template.InvalidParameter":    "❌ Неверное значение %s: %s\nОжидается: %s",
+		"template.PermissionDenied":    "❌ Доступ запрещён: %s",
+		"template.RateLimited":         "⏱️ Не так быстро! Эту команду можно будет использовать снова через %s.",
+		"template.InternalError":       "❌ Произошла внутренняя ошибка. Попробуйте позже.",
+		"template.FeatureNotAvailable": "🚧 Эта функция сейчас недоступна.",
+
+		"error.internal":      "Во время выполнения команды что-то пошло не так.",
+		"error.timeout":       "Команда выполнялась слишком долго и была остановлена.",
+		"error.failed":        "Не удалось выполнить команду. Попробуйте позже.",
+		"error.role_required": "Для этой команды нужны права %s",
+
+		"job.panic":     "Во время выполнения задачи что-то пошло не так",
+		"job.failed":    "/%s не удалось выполнить",
+		"job.cancelled": "/%s отменена",
+		"job.timeout":   "/%s выполнялась слишком долго и была остановлена",
+
+		"suggest.did_you_mean": "Возможно, вы имели в виду %s?",
+		"list.or":              "или",
+		"usage.line":           "Использование: `%s`",
+		"dispatch.busy":        "В этом чате ожидает слишком много команд, попробуйте чуть позже",
+
+		"afk.reply":    "Человек, с которым вы пытаетесь связаться, сейчас недоступен. Если дело срочное, позвоните.",
+		"afk.disabled": "❌ Режим AFK выключен.",
+
+		"jobs.none":      "Нет запущенных задач",
+		"jobs.title":     "⚙️ Запущенные задачи",
+		"jobs.in_chat":   "(в %s)",
+		"jobs.hint":      "Отправьте `/cancel <id>` или ответьте `/cancel` на сообщение о статусе задачи",
+		"cancel.unknown": "Нет запущенной задачи %s",
+		"cancel.usage":   "Укажите ID задачи (см. /jobs) или ответьте на сообщение о статусе задачи",
+		"cancel.not_job": "Цитируемое сообщение не относится к запущенной задаче",
+		"cancel.done":    "Задача #%s (/%s) отменена",
+
+		"help.title":           "📋 Доступные команды",
+		"help.hint":            "Отправьте `/help <command>` для подробностей, `/help <category>` для категории или `/help search <term>` для поиска",
+		"help.no_match":        "Нет команд, подходящих под %q",
+		"help.search_title":    "🔎 Команды по запросу \"%s\"",
+		"help.category_empty":  "Здесь вам недоступны команды категории %s",
+		"help.unknown":         "Нет команды или категории `%s`",
+		"help.page":            "Страница %d из %d",
+		"help.next_page":       "Отправьте `%s %d` для следующей страницы",
+		"help.command":         "*Команда:* *%s*",
+		"help.aliases":         "*Псевдонимы:* %s",
+		"help.description":     "*Описание:* %s",
+		"help.usage":           "*Использование:* `%s`",
+		"help.parameters":      "*Параметры:*",
+		"help.required":        "*(обязательно)*",
+		"help.flags":           "*Флаги:*",
+		"help.default":         "(по умолчанию: %v)",
+		"help.examples":        "*Примеры:*",
+		"help.rate_limit":      "*Ограничение:* %s",
+		"help.requires_role":   "*Для этой команды нужны права %s*",
+		"help.subcommands":     "*Подкоманды:*",
+		"help.subcommand_hint": "Отправьте `/help %s <subcommand>` для подробностей",
+		"help.other_commands":  "Другие команды",
+		"owner.required":       "Эта команда доступна только владельцу",
+
+		"uilang.title":           "🌐 Язык бота",
+		"uilang.usage":           "Использование: `%s`",
+		"uilang.unsupported":     "Язык `%s` не поддерживается, выберите один из: %s",
+		"uilang.failed":          "Не удалось сохранить язык: %v",
+		"uilang.failed_load":     "Не удалось загрузить язык: %v",
+		"uilang.current_user":    "Вы выбрали %s",
+		"uilang.current_chat":    "В этом чате используется %s",
+		"uilang.current_default": "Язык по умолчанию: %s",
+		"uilang.hint":            "Отправьте `/uilang <code>`, чтобы изменить его (%s)",
+		"uilang.user_set":        "Теперь бот отвечает вам на языке: %s",
+		"uilang.chat_set":        "Теперь бот отвечает в этом чате на языке: %s",
+		"uilang.user_reset":      "Ваш выбор языка сброшен",
+		"uilang.chat_reset":      "В этом чате снова используется язык по умолчанию",
+	},
+}
@@ -0,0 +1,215 @@
+// Package i18n localizes the bot's own replies. Every UI string has a key
+// and an English text given at the call site; the other languages come from
+// the built-in catalogs and, for keys they lack, from machine translations
+// that are cached once made.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+)
+
+// DefaultLanguage is the language of the texts given at call sites.
+const DefaultLanguage = "en"
+
+// translateTimeout bounds a single background translation.
+const translateTimeout = 30 * time.Second
+
+// TranslateFunc translates English text into lang.
+type TranslateFunc func(ctx context.Context, text, lang string) (string, error)
+
+// Cache persists machine translations. source is the English text that was
+// translated, so a translation is dropped once the English text changes.
+type Cache interface {
+	LoadUIText(lang, key string) (source, text string, ok bool, err error)
+	SaveUIText(lang, key, source, text string) error
+}
+
+// Catalog resolves UI strings for any language. It is safe for concurrent
+// use.
+type Catalog struct {
+	translate TranslateFunc
+	cache     Cache
+
+	mu      sync.Mutex
+	memory  map[string]cached
+	pending map[string]bool
+}
+
+type cached struct {
+	source string
+	text   string
+}
+
+// NewCatalog creates a catalog that fills gaps in the built-in catalogs
+// using translate; translate and cache may be nil.
+func NewCatalog(translate TranslateFunc, cache Cache) *Catalog {
+	return &Catalog{
+		translate: translate,
+		cache:     cache,
+		memory:    make(map[string]cached),
+		pending:   make(map[string]bool),
+	}
+}
+
+// Localizer returns the localizer for lang; an empty lang means English.
+func (c *Catalog) Localizer(lang string) *Localizer {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	return &Localizer{catalog: c, lang: lang}
+}
+
+// lookup returns the translation of english into lang, or english itself
+// while a machine translation is pending or failed.
+func (c *Catalog) lookup(lang, key, english string) string {
+	id := lang + "\x00" + key
+
+	c.mu.Lock()
+	entry, ok := c.memory[id]
+	c.mu.Unlock()
+	if ok && entry.source == english {
+		return entry.text
+	}
+
+	if c.cache != nil {
+		source, text, ok, err := c.cache.LoadUIText(lang, key)
+		if err != nil {
+			logging.Log.Error().Err(err).Str("lang", lang).Str("key", key).Msg("Failed to load cached UI string")
+		} else if ok && source == english {
+			c.remember(id, english, text)
+			return text
+		}
+	}
+
+	c.translateLater(id, lang, key, english)
+	return english
+}
+
+func (c *Catalog) remember(id, source, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.memory[id] = cached{source: source, text: text}
+}
+
+// translateLater machine translates english in the background, so the reply
+// at hand isn't delayed; later replies use the result.
+func (c *Catalog) translateLater(id, lang, key, english string) {
+	if c.translate == nil {
+		return
+	}
+
+	c.mu.Lock()
+	if c.pending[id] {
+		c.mu.Unlock()
+		return
+	}
+	c.pending[id] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.pending, id)
+			c.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), translateTimeout)
+		defer cancel()
+
+		protected, verbs := protectVerbs(english)
+		translated, err := c.translate(ctx, protected, lang)
+		if err == nil {
+			translated, err = restoreVerbs(translated, verbs)
+		}
+		if err != nil {
+			logging.Log.Warn().Err(err).Str("lang", lang).Str("key", key).Msg("Failed to translate UI string")
+			// Keep English until the text changes instead of retrying on
+			// every reply
+			c.remember(id, english, english)
+			return
+		}
+
+		c.remember(id, english, translated)
+		if c.cache != nil {
+			if err := c.cache.SaveUIText(lang, key, english, translated); err != nil {
+				logging.Log.Error().Err(err).Str("lang", lang).Str("key", key).Msg("Failed to cache UI string")
+			}
+		}
+	}()
+}
+
+// Localizer renders UI strings in one language. A nil Localizer renders
+// English.
+type Localizer struct {
+	catalog *Catalog
+	lang    string
+}
+
+// In returns a localizer for lang that shares l's catalog.
+func (l *Localizer) In(lang string) *Localizer {
+	if l == nil || l.catalog == nil {
+		return &Localizer{lang: lang}
+	}
+	return l.catalog.Localizer(lang)
+}
+
+// Lang is the language code of the localizer.
+func (l *Localizer) Lang() string {
+	if l == nil {
+		return DefaultLanguage
+	}
+	return l.lang
+}
+
+// T returns the text of key, whose English text is english, formatted with
+// args like fmt.Sprintf.
+func (l *Localizer) T(key, english string, args ...interface{}) string {
+	text := english
+	if l != nil && l.lang != DefaultLanguage {
+		if builtin, ok := catalogs[l.lang][key]; ok {
+			text = builtin
+		} else if l.catalog != nil {
+			text = l.catalog.lookup(l.lang, key, english)
+		}
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// protectVerbs replaces the fmt verbs in text with numbered placeholders a
+// translator leaves alone.
+func protectVerbs(text string) (string, []string) {
+	var verbs []string
+	protected := verbPattern.ReplaceAllStringFunc(text, func(verb string) string {
+		verbs = append(verbs, verb)
+		return "{" + strconv.Itoa(len(verbs)) + "}"
+	})
+	return protected, verbs
+}
+
+// restoreVerbs reverses protectVerbs and fails if the translation lost or
+// duplicated a placeholder.
+func restoreVerbs(text string, verbs []string) (string, error) {
+	for i, verb := range verbs {
+		placeholder := "{" + strconv.Itoa(i+1) + "}"
+		if strings.Count(text, placeholder) != 1 {
+			return "", fmt.Errorf("translation mangled placeholder %s", placeholder)
+		}
+		text = strings.Replace(text, placeholder, verb, 1)
+	}
+	return text, nil
+}
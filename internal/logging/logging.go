@@ -0,0 +1,116 @@
+// Package logging provides the process-wide structured logger. Everything it
+// writes passes through a redactor so API keys and tokens never reach the
+// logs.
+package logging
+
+import (
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Log is the root logger. It is usable before Setup, logging at info level
+// to stderr.
+var Log = newLogger(os.Stderr, zerolog.InfoLevel, "console", nil)
+
+// configuredSecrets holds the secrets given to Setup, for RedactSecrets.
+var configuredSecrets []string
+
+// Setup configures Log. level is a zerolog level name (debug, info, warn,
+// error), format is "console" or "json", and secrets are values that must
+// never be logged verbatim. Output of the standard log package is routed
+// through Log as well.
+func Setup(level, format string, secrets ...string) {
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || level == "" {
+		lvl = zerolog.InfoLevel
+	}
+
+	configuredSecrets = filterSecrets(secrets)
+	Log = newLogger(os.Stderr, lvl, format, secrets)
+	if err != nil {
+		Log.Warn().Str("level", level).Msg("Unknown LOG_LEVEL, using info")
+	}
+
+	log.SetFlags(0)
+	log.SetOutput(stdlogWriter{})
+}
+
+func newLogger(out io.Writer, level zerolog.Level, format string, secrets []string) zerolog.Logger {
+	out = &redactor{w: out, secrets: filterSecrets(secrets)}
+	if format != "json" {
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.DateTime}
+	}
+	return zerolog.New(out).Level(level).With().Timestamp().Logger()
+}
+
+// stdlogWriter forwards standard log output to Log.
+type stdlogWriter struct{}
+
+func (stdlogWriter) Write(p []byte) (int, error) {
+	Log.Info().Msg(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+var secretPatterns = []*regexp.Regexp{
+	// query parameters such as ?key=... or &access_token=...
+	regexp.MustCompile(`(?i)([?&](?:key|api_?key|token|access_token|secret)=)[^&\s"']+`),
+	// Authorization headers
+	regexp.MustCompile(`(?i)(bearer\s+)[a-z0-9._~+/=-]+`),
+	// OpenRouter / OpenAI style keys
+	regexp.MustCompile(`(sk-[a-z]*-?)[A-Za-z0-9_-]{16,}`),
+}
+
+// redactor masks secrets in everything written through it. The console
+// writer formats before writing, so redaction applies to both formats.
+type redactor struct {
+	mu      sync.Mutex
+	w       io.Writer
+	secrets []string
+}
+
+func (r *redactor) Write(p []byte) (int, error) {
+	text := Redact(string(p), r.secrets...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.w.Write([]byte(text)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Redact masks known secret patterns and the given secret values in s.
+func Redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, "[REDACTED]")
+	}
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}[REDACTED]")
+	}
+	return s
+}
+
+// RedactSecrets is Redact with the secrets given to Setup, for text stored
+// outside the logs such as the audit log.
+func RedactSecrets(s string) string {
+	return Redact(s, configuredSecrets...)
+}
+
+// filterSecrets drops values too short to be secrets, which would otherwise
+// mask unrelated text.
+func filterSecrets(secrets []string) []string {
+	var result []string
+	for _, secret := range secrets {
+		if len(secret) >= 8 {
+			result = append(result, secret)
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrTooManyPending is returned by AddAccount while too many accounts
+	// are still waiting to be paired
+	ErrTooManyPending = errors.New("too many accounts waiting to be paired")
+	// ErrAccountLinked is returned by RemoveAccount for a linked account,
+	// which has to be unlinked on the phone first
+	ErrAccountLinked = errors.New("account is linked; unlink it on the phone first")
+)
+
+// AccountHandler is everything the HTTP server needs from a single WhatsApp
+// account.
+type AccountHandler interface {
+	HealthSource
+	PairingSource
+	APIBackend
+}
+
+type Account struct {
+	ID      string
+	JID     types.JID
+	Handler AccountHandler
+}
+
+// AccountProvider gives the server access to every account driven by this
+// process.
+type AccountProvider interface {
+	Accounts() []Account
+	Account(id string) (Account, bool)
+	AddAccount() (Account, error)
+	// RemoveAccount stops an account that isn't linked and forgets it
+	RemoveAccount(id string) error
+}
+
+type AccountSummary struct {
+	ID      string `json:"id"`
+	JID     string `json:"jid,omitempty"`
+	State   string `json:"state"`
+	PairURL string `json:"pair_url,omitempty"`
+}
+
+type accountsHandler struct {
+	accounts AccountProvider
+}
+
+// RegisterAccountRoutes exposes listing, adding and removing accounts. New
+// accounts start in pairing mode and can be linked through their pair_url;
+// accounts that were never linked can be removed again.
+func RegisterAccountRoutes(s *Server, accounts AccountProvider, token string) {
+	h := &accountsHandler{accounts: accounts}
+	auth := tokenAuth(token)
+	s.Handle("GET /accounts", auth(http.HandlerFunc(h.list)))
+	s.Handle("POST /accounts", auth(http.HandlerFunc(h.add)))
+	s.Handle("DELETE /accounts/{id}", auth(http.HandlerFunc(h.remove)))
+}
+
+func (h *accountsHandler) list(w http.ResponseWriter, r *http.Request) {
+	accounts := h.accounts.Accounts()
+	summaries := make([]AccountSummary, 0, len(accounts))
+	for _, account := range accounts {
+		summaries = append(summaries, summarize(account))
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (h *accountsHandler) add(w http.ResponseWriter, r *http.Request) {
+	account, err := h.accounts.AddAccount()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, summarize(account))
+}
+
+func (h *accountsHandler) remove(w http.ResponseWriter, r *http.Request) {
+	if err := h.accounts.RemoveAccount(r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func summarize(account Account) AccountSummary {
+	summary := AccountSummary{
+		ID:    account.ID,
+		State: account.Handler.PairingStatus().State,
+	}
+	if !account.JID.IsEmpty() {
+		summary.JID = account.JID.String()
+	}
+	if summary.State != PairingPaired {
+		summary.PairURL = "/accounts/" + account.ID + "/pair"
+	}
+	return summary
+}
+
+// resolveAccount picks the account named by id, or the first logged-in
+// account when id is empty.
+func resolveAccount(accounts AccountProvider, id string) (Account, error) {
+	if id != "" {
+		account, ok := accounts.Account(id)
+		if !ok {
+			return Account{}, ErrAccountNotFound
+		}
+		return account, nil
+	}
+
+	all := accounts.Accounts()
+	for _, account := range all {
+		if account.Handler.ConnectionState().LoggedIn {
+			return account, nil
+		}
+	}
+	if len(all) > 0 {
+		return all[0], nil
+	}
+	return Account{}, ErrAccountNotFound
+}
@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxRequestBody caps API request bodies; inline media is base64 encoded so
+// this leaves room for roughly 24MB of payload.
+const maxRequestBody = 32 << 20
+
+var (
+	ErrCommandNotFound     = errors.New("command not found")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrNotLoggedIn         = errors.New("whatsapp client is not logged in")
+	ErrInvalidArguments    = errors.New("invalid arguments")
+)
+
+// APIBackend is implemented by the event handler and performs the actual work
+// behind the REST API.
+type APIBackend interface {
+	Translate(ctx context.Context, text, source, target string) (translation string, detected string, err error)
+	SendText(ctx context.Context, to types.JID, text string) (types.MessageID, error)
+	SendMedia(ctx context.Context, to types.JID, mediaType framework.MediaType, data []byte, caption, filename string) error
+	// ExecuteCommand runs a registry command as the account owner and returns
+	// the text responses the command produced, in order. Jobs started by the
+	// command are waited for, so their final status is included.
+	ExecuteCommand(ctx context.Context, chat types.JID, name string, args string) ([]string, error)
+}
+
+type TranslateRequest struct {
+	Account string `json:"account,omitempty"`
+	Text    string `json:"text"`
+	Source  string `json:"source,omitempty"`
+	Target  string `json:"target"`
+}
+
+type TranslateResponse struct {
+	Translation string `json:"translation"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+}
+
+type SendRequest struct {
+	Account string        `json:"account,omitempty"`
+	JID     string        `json:"jid"`
+	Text    string        `json:"text,omitempty"`
+	Media   *MediaPayload `json:"media,omitempty"`
+}
+
+// MediaPayload describes an attachment for /v1/send. Exactly one of URL and
+// Data (base64) must be set.
+type MediaPayload struct {
+	Type     string `json:"type"`
+	URL      string `json:"url,omitempty"`
+	Data     string `json:"data,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+type SendResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type CommandRequest struct {
+	Account string `json:"account,omitempty"`
+	Args    string `json:"args,omitempty"`
+	JID     string `json:"jid,omitempty"`
+}
+
+type CommandResponse struct {
+	Command   string   `json:"command"`
+	Response  string   `json:"response"`
+	Responses []string `json:"responses"`
+}
+
+type ErrorBody struct {
+	Error string `json:"error"`
+}
+
+type apiHandler struct {
+	accounts AccountProvider
+}
+
+// RegisterAPIRoutes exposes the authenticated /v1 API. Every route requires an
+// "Authorization: Bearer <token>" header matching token. Requests act as the
+// account named in their "account" field, or the first logged-in account.
+func RegisterAPIRoutes(s *Server, accounts AccountProvider, token string) {
+	h := &apiHandler{accounts: accounts}
+	s.Handle("POST /v1/translate", requireBearer(token, http.HandlerFunc(h.translate)))
+	s.Handle("POST /v1/send", requireBearer(token, http.HandlerFunc(h.send)))
+	s.Handle("POST /v1/commands/{name}", requireBearer(token, http.HandlerFunc(h.command)))
+}
+
+func requireBearer(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="whatsapp-livetranslate"`)
+			writeJSON(w, http.StatusUnauthorized, ErrorBody{Error: "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *apiHandler) translate(w http.ResponseWriter, r *http.Request) {
+	var req TranslateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" || req.Target == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorBody{Error: "text and target are required"})
+		return
+	}
+
+	account, err := resolveAccount(h.accounts, req.Account)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	translation, detected, err := account.Handler.Translate(r.Context(), req.Text, req.Source, req.Target)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TranslateResponse{
+		Translation: translation,
+		Source:      detected,
+		Target:      req.Target,
+	})
+}
+
+func (h *apiHandler) send(w http.ResponseWriter, r *http.Request) {
+	var req SendRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := resolveAccount(h.accounts, req.Account)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	to, err := parseJID(req.JID)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorBody{Error: err.Error()})
+		return
+	}
+
+	if req.Media == nil {
+		if strings.TrimSpace(req.Text) == "" {
+			writeJSON(w, http.StatusBadRequest, ErrorBody{Error: "either text or media is required"})
+			return
+		}
+		id, err := account.Handler.SendText(r.Context(), to, req.Text)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, SendResponse{Status: "sent", MessageID: id})
+		return
+	}
+
+	mediaType, err := parseMediaType(req.Media.Type)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorBody{Error: err.Error()})
+		return
+	}
+
+	data, err := loadMedia(r.Context(), req.Media)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorBody{Error: err.Error()})
+		return
+	}
+
+	caption := req.Media.Caption
+	if caption == "" {
+		caption = req.Text
+	}
+
+	if err := account.Handler.SendMedia(r.Context(), to, mediaType, data, caption, req.Media.Filename); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, SendResponse{Status: "sent"})
+}
+
+func (h *apiHandler) command(w http.ResponseWriter, r *http.Request) {
+	var req CommandRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+
+	account, err := resolveAccount(h.accounts, req.Account)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var chat types.JID
+	if req.JID != "" {
+		if chat, err = parseJID(req.JID); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorBody{Error: err.Error()})
+			return
+		}
+	}
+
+	name := r.PathValue("name")
+	responses, err := account.Handler.ExecuteCommand(r.Context(), chat, name, req.Args)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := CommandResponse{Command: name, Responses: responses}
+	if len(responses) > 0 {
+		resp.Response = responses[len(responses)-1]
+	} else {
+		resp.Responses = []string{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorBody{Error: fmt.Sprintf("invalid JSON body: %v", err)})
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, ErrCommandNotFound), errors.Is(err, ErrAccountNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrUnsupportedLanguage), errors.Is(err, ErrInvalidArguments):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrNotLoggedIn):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, ErrTooManyPending), errors.Is(err, ErrAccountLinked):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, ErrorBody{Error: err.Error()})
+}
+
+// parseJID accepts either a full JID or a bare international phone number.
+func parseJID(raw string) (types.JID, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return types.EmptyJID, fmt.Errorf("jid is required")
+	}
+	if !strings.Contains(raw, "@") {
+		raw = strings.TrimPrefix(raw, "+") + "@" + types.DefaultUserServer
+	}
+	jid, err := types.ParseJID(raw)
+	if err != nil {
+		return types.EmptyJID, fmt.Errorf("invalid jid: %w", err)
+	}
+	return jid, nil
+}
+
+func parseMediaType(raw string) (framework.MediaType, error) {
+	switch strings.ToLower(raw) {
+	case "image":
+		return framework.MediaImage, nil
+	case "video":
+		return framework.MediaVideo, nil
+	case "document":
+		return framework.MediaDocument, nil
+	case "audio":
+		return framework.MediaAudio, nil
+	default:
+		return 0, fmt.Errorf("unsupported media type %q (expected image, video, document or audio)", raw)
+	}
+}
+
+func loadMedia(ctx context.Context, media *MediaPayload) ([]byte, error) {
+	switch {
+	case media.URL != "" && media.Data != "":
+		return nil, fmt.Errorf("media.url and media.data are mutually exclusive")
+	case media.URL != "":
+		return fetchMedia(ctx, media.URL)
+	case media.Data != "":
+		data, err := base64.StdEncoding.DecodeString(media.Data)
+		if err != nil {
+			return nil, fmt.Errorf("media.data is not valid base64: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("media.url or media.data is required")
+	}
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// probeTTL bounds how often the translator backend is actually contacted, so a
+// tight healthcheck interval doesn't turn into a stream of API calls.
+const probeTTL = 30 * time.Second
+
+// ConnectionState is a snapshot of the WhatsApp connection as seen by the
+// event handler.
+type ConnectionState struct {
+	Connected         bool
+	LoggedIn          bool
+	LoggedOut         bool
+	Pairing           bool
+	LastEventAt       time.Time
+	DisconnectedSince time.Time
+}
+
+type HealthSource interface {
+	ConnectionState() ConnectionState
+	ProbeTranslator(ctx context.Context) error
+}
+
+type HealthResponse struct {
+	Status   string          `json:"status"`
+	Accounts []AccountHealth `json:"accounts"`
+}
+
+type AccountHealth struct {
+	Account           string     `json:"account"`
+	JID               string     `json:"jid,omitempty"`
+	Status            string     `json:"status"`
+	Connected         bool       `json:"connected"`
+	LoggedIn          bool       `json:"logged_in"`
+	Pairing           bool       `json:"pairing,omitempty"`
+	LastEventAt       *time.Time `json:"last_event_at,omitempty"`
+	DisconnectedSince *time.Time `json:"disconnected_since,omitempty"`
+	Translator        string     `json:"translator,omitempty"`
+	Reason            string     `json:"reason,omitempty"`
+}
+
+type probeResult struct {
+	at  time.Time
+	err error
+}
+
+type probeFetch struct {
+	done chan struct{}
+	err  error
+}
+
+type healthHandler struct {
+	accounts        AccountProvider
+	disconnectGrace time.Duration
+
+	mu     sync.Mutex
+	probes map[string]probeResult
+	// fetches holds the probes in flight, shared by every request asking
+	// about the same account meanwhile
+	fetches map[string]*probeFetch
+}
+
+// RegisterHealthRoutes exposes /healthz (liveness) and /readyz (readiness).
+//
+// Liveness only fails when restarting the container can actually help: an
+// account was logged out, or has been disconnected for longer than
+// disconnectGrace. Waiting for a device to be linked is considered healthy.
+// Readiness requires every linked account to be connected, logged in and have
+// a reachable translator backend.
+func RegisterHealthRoutes(s *Server, accounts AccountProvider, disconnectGrace time.Duration) {
+	h := &healthHandler{
+		accounts:        accounts,
+		disconnectGrace: disconnectGrace,
+		probes:          make(map[string]probeResult),
+		fetches:         make(map[string]*probeFetch),
+	}
+	s.HandleFunc("GET /healthz", h.healthz)
+	s.HandleFunc("GET /readyz", h.readyz)
+}
+
+func (h *healthHandler) healthz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: "ok"}
+
+	for _, account := range h.accounts.Accounts() {
+		state := account.Handler.ConnectionState()
+		health := newAccountHealth(account, state)
+
+		switch {
+		case state.Pairing:
+			health.Reason = "waiting for pairing"
+		case state.LoggedOut:
+			health.Status = "unhealthy"
+			health.Reason = "logged out"
+		case !state.DisconnectedSince.IsZero() && time.Since(state.DisconnectedSince) > h.disconnectGrace:
+			health.Status = "unhealthy"
+			health.Reason = "disconnected for " + time.Since(state.DisconnectedSince).Round(time.Second).String()
+		}
+
+		if health.Status != "ok" {
+			resp.Status = "unhealthy"
+		}
+		resp.Accounts = append(resp.Accounts, health)
+	}
+
+	writeJSON(w, statusCode(resp), resp)
+}
+
+func (h *healthHandler) readyz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: "ok"}
+	ready := 0
+
+	for _, account := range h.accounts.Accounts() {
+		state := account.Handler.ConnectionState()
+		health := newAccountHealth(account, state)
+
+		if err := h.probe(r.Context(), account); err != nil {
+			health.Translator = err.Error()
+		} else {
+			health.Translator = "ok"
+		}
+
+		switch {
+		case state.Pairing:
+			health.Status = "unavailable"
+			health.Reason = "waiting for pairing"
+		case state.LoggedOut:
+			health.Status = "unavailable"
+			health.Reason = "logged out"
+		case !state.LoggedIn:
+			health.Status = "unavailable"
+			health.Reason = "not logged in"
+		case !state.Connected:
+			health.Status = "unavailable"
+			health.Reason = "not connected"
+		case health.Translator != "ok":
+			health.Status = "unavailable"
+			health.Reason = "translator unreachable"
+		}
+
+		// An account that is still being linked doesn't make the others
+		// unready.
+		if health.Status == "ok" {
+			ready++
+		} else if !state.Pairing {
+			resp.Status = "unavailable"
+		}
+		resp.Accounts = append(resp.Accounts, health)
+	}
+
+	if ready == 0 {
+		resp.Status = "unavailable"
+	}
+	writeJSON(w, statusCode(resp), resp)
+}
+
+// probe returns the cached translator probe result for an account, refreshing
+// it when stale. Concurrent requests share a single refresh, which isn't
+// cancelled when the request that started it goes away.
+func (h *healthHandler) probe(ctx context.Context, account Account) error {
+	h.mu.Lock()
+	if result, ok := h.probes[account.ID]; ok && time.Since(result.at) < probeTTL {
+		h.mu.Unlock()
+		return result.err
+	}
+	fetch, inFlight := h.fetches[account.ID]
+	if !inFlight {
+		fetch = &probeFetch{done: make(chan struct{})}
+		h.fetches[account.ID] = fetch
+	}
+	h.mu.Unlock()
+
+	if !inFlight {
+		go h.refreshProbe(context.WithoutCancel(ctx), account, fetch)
+	}
+
+	select {
+	case <-fetch.done:
+		return fetch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *healthHandler) refreshProbe(ctx context.Context, account Account, fetch *probeFetch) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	fetch.err = account.Handler.ProbeTranslator(ctx)
+
+	h.mu.Lock()
+	delete(h.fetches, account.ID)
+	h.probes[account.ID] = probeResult{at: time.Now(), err: fetch.err}
+	h.mu.Unlock()
+	close(fetch.done)
+}
+
+func newAccountHealth(account Account, state ConnectionState) AccountHealth {
+	health := AccountHealth{
+		Account:   account.ID,
+		Status:    "ok",
+		Connected: state.Connected,
+		LoggedIn:  state.LoggedIn,
+		Pairing:   state.Pairing,
+	}
+	if !account.JID.IsEmpty() {
+		health.JID = account.JID.String()
+	}
+	if !state.LastEventAt.IsZero() {
+		health.LastEventAt = &state.LastEventAt
+	}
+	if !state.DisconnectedSince.IsZero() {
+		health.DisconnectedSince = &state.DisconnectedSince
+	}
+	return health
+}
+
+func statusCode(resp HealthResponse) int {
+	if resp.Status == "ok" {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxMediaSize caps media fetched from a media.url, the same as inline
+	// media allowed by maxRequestBody.
+	maxMediaSize = 24 << 20
+
+	mediaFetchTimeout = 30 * time.Second
+	maxMediaRedirects = 5
+)
+
+// errPrivateAddress is returned for media URLs pointing into the host's own
+// network, which API callers must not be able to reach through the bot.
+var errPrivateAddress = errors.New("media.url must point to a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, not covered by
+// netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// mediaClient fetches media URLs. The address is checked after name
+// resolution, on every connection including redirects, and proxies from the
+// environment are not used so the check can't be bypassed.
+var mediaClient = &http.Client{
+	Timeout: mediaFetchTimeout,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: publicAddressOnly,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxMediaRedirects {
+			return fmt.Errorf("media.url redirected more than %d times", maxMediaRedirects)
+		}
+		return checkMediaScheme(req.URL)
+	},
+}
+
+// fetchMedia downloads a media.url of an API request.
+func fetchMedia(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("media.url is not a valid URL: %w", err)
+	}
+	if err := checkMediaScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("media.url is not a valid URL: %w", err)
+	}
+	resp, err := mediaClient.Do(req)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return nil, errPrivateAddress
+		}
+		return nil, fmt.Errorf("failed to download media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download media: status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxMediaSize {
+		return nil, fmt.Errorf("media is larger than %dMB", maxMediaSize>>20)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media data: %w", err)
+	}
+	if len(data) > maxMediaSize {
+		return nil, fmt.Errorf("media is larger than %dMB", maxMediaSize>>20)
+	}
+	return data, nil
+}
+
+func checkMediaScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("media.url must be an http or https URL")
+	}
+	return nil
+}
+
+// publicAddressOnly is a net.Dialer Control function refusing connections to
+// loopback, private, link-local and other non-public addresses.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) {
+		return errPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"rsc.io/qr"
+)
+
+const (
+	PairingIdle    = "idle"
+	PairingWaiting = "waiting"
+	PairingPaired  = "paired"
+	PairingError   = "error"
+)
+
+// PairingStatus describes the current state of device linking.
+type PairingStatus struct {
+	State     string    `json:"state"`
+	QRCode    string    `json:"-"`
+	PairCode  string    `json:"pair_code,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type PairingSource interface {
+	PairingStatus() PairingStatus
+}
+
+type pairingHandler struct {
+	accounts AccountProvider
+	token    string
+}
+
+// RegisterPairingRoutes exposes the linking flow over HTTP for every account:
+// /accounts/{id}/pair renders a page that keeps the QR code (or phone pairing
+// code) current, .../pair/qr.png serves the QR code itself and
+// .../pair/status reports progress as JSON. /pair redirects to the first
+// account that is waiting to be linked.
+//
+// When token is non-empty it must be supplied either as a bearer token or as
+// a "token" query parameter, so the page can be opened in a browser.
+func RegisterPairingRoutes(s *Server, accounts AccountProvider, token string) {
+	h := &pairingHandler{accounts: accounts, token: token}
+	auth := tokenAuth(token)
+	s.Handle("GET /pair", auth(http.HandlerFunc(h.redirect)))
+	s.Handle("GET /accounts/{id}/pair", auth(http.HandlerFunc(h.page)))
+	s.Handle("GET /accounts/{id}/pair/qr.png", auth(http.HandlerFunc(h.qrImage)))
+	s.Handle("GET /accounts/{id}/pair/status", auth(http.HandlerFunc(h.status)))
+}
+
+func (h *pairingHandler) redirect(w http.ResponseWriter, r *http.Request) {
+	accounts := h.accounts.Accounts()
+	if len(accounts) == 0 {
+		http.Error(w, "no accounts configured", http.StatusNotFound)
+		return
+	}
+
+	target := accounts[0]
+	for _, account := range accounts {
+		if account.Handler.PairingStatus().State != PairingPaired {
+			target = account
+			break
+		}
+	}
+
+	location := "/accounts/" + url.PathEscape(target.ID) + "/pair"
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, location, http.StatusFound)
+}
+
+func (h *pairingHandler) account(w http.ResponseWriter, r *http.Request) (Account, bool) {
+	account, ok := h.accounts.Account(r.PathValue("id"))
+	if !ok {
+		http.Error(w, ErrAccountNotFound.Error(), http.StatusNotFound)
+	}
+	return account, ok
+}
+
+func (h *pairingHandler) status(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.account(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, account.Handler.PairingStatus())
+}
+
+func (h *pairingHandler) qrImage(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.account(w, r)
+	if !ok {
+		return
+	}
+
+	status := account.Handler.PairingStatus()
+	if status.QRCode == "" {
+		http.Error(w, "no QR code available", http.StatusNotFound)
+		return
+	}
+
+	code, err := qr.Encode(status.QRCode, qr.L)
+	if err != nil {
+		http.Error(w, "failed to render QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(code.PNG())
+}
+
+func (h *pairingHandler) page(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.account(w, r)
+	if !ok {
+		return
+	}
+
+	query := ""
+	if h.token != "" {
+		query = "?token=" + url.QueryEscape(r.URL.Query().Get("token"))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = pairingPage.Execute(w, struct {
+		Account string
+		Base    string
+		Query   string
+	}{
+		Account: account.ID,
+		Base:    "/accounts/" + url.PathEscape(account.ID) + "/pair",
+		Query:   query,
+	})
+}
+
+var pairingPage = template.Must(template.New("pair").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Link WhatsApp LiveTranslate</title>
+<style>
+body { font-family: sans-serif; text-align: center; margin-top: 3em; }
+#code { font-size: 2em; letter-spacing: .2em; font-family: monospace; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<h1>Link WhatsApp LiveTranslate</h1>
+<p>Account: <code>{{.Account}}</code></p>
+<p id="state">Loading...</p>
+<img id="qr" class="hidden" alt="WhatsApp QR code" width="320" height="320">
+<div id="pair" class="hidden">
+<p>Or open WhatsApp &rarr; Linked devices &rarr; Link with phone number and enter:</p>
+<p id="code"></p>
+</div>
+<script>
+const base = "{{.Base}}";
+const query = "{{.Query}}";
+let lastQR = "";
+async function refresh() {
+	try {
+		const res = await fetch(base + "/status" + query, {cache: "no-store"});
+		const status = await res.json();
+		const qr = document.getElementById("qr");
+		const pair = document.getElementById("pair");
+		const state = document.getElementById("state");
+		if (status.state === "paired") {
+			state.textContent = "Paired ✅ You can close this page.";
+			qr.classList.add("hidden");
+			pair.classList.add("hidden");
+			return;
+		}
+		state.textContent = status.state === "error"
+			? "Pairing failed: " + status.error + " (retrying)"
+			: "Scan the QR code with WhatsApp → Linked devices.";
+		if (status.state === "waiting" && status.updated_at !== lastQR) {
+			lastQR = status.updated_at;
+			qr.src = base + "/qr.png" + query + (query ? "&" : "?") + "t=" + Date.now();
+			qr.classList.remove("hidden");
+		}
+		if (status.pair_code) {
+			document.getElementById("code").textContent = status.pair_code;
+			pair.classList.remove("hidden");
+		}
+	} catch (e) {
+		document.getElementById("state").textContent = "Bot unreachable, retrying...";
+	}
+	setTimeout(refresh, 3000);
+}
+refresh();
+</script>
+</body>
+</html>
+`))
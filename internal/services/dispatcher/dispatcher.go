@@ -0,0 +1,135 @@
+// Package dispatcher runs message handling concurrently while keeping the
+// messages of each chat in order.
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+)
+
+var (
+	// ErrQueueFull is returned when a chat already has the maximum number of
+	// tasks waiting.
+	ErrQueueFull = errors.New("queue is full")
+	// ErrClosed is returned once Drain has been called.
+	ErrClosed = errors.New("dispatcher is closed")
+)
+
+// Dispatcher runs tasks on at most workers goroutines at a time. Tasks with
+// the same key (a chat) run one after another in submission order, while
+// tasks with different keys run in parallel.
+type Dispatcher struct {
+	slots    chan struct{}
+	maxQueue int
+
+	mu     sync.Mutex
+	lanes  map[string]*lane
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// lane holds the pending tasks of one key. A lane exists while it has a task
+// running or waiting and is served by a single goroutine.
+type lane struct {
+	tasks []func()
+}
+
+// New creates a dispatcher running up to workers tasks at once and holding up
+// to maxQueue waiting tasks per key.
+func New(workers, maxQueue int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxQueue < 1 {
+		maxQueue = 1
+	}
+	return &Dispatcher{
+		slots:    make(chan struct{}, workers),
+		maxQueue: maxQueue,
+		lanes:    make(map[string]*lane),
+	}
+}
+
+// Submit queues task behind earlier tasks with the same key.
+func (d *Dispatcher) Submit(key string, task func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	l, exists := d.lanes[key]
+	if !exists {
+		l = &lane{}
+		d.lanes[key] = l
+		go d.run(key, l)
+	}
+	if len(l.tasks) >= d.maxQueue {
+		return ErrQueueFull
+	}
+
+	l.tasks = append(l.tasks, task)
+	d.wg.Add(1)
+	return nil
+}
+
+// run executes the tasks of a lane until it is empty.
+func (d *Dispatcher) run(key string, l *lane) {
+	for {
+		d.mu.Lock()
+		if len(l.tasks) == 0 {
+			delete(d.lanes, key)
+			d.mu.Unlock()
+			return
+		}
+		task := l.tasks[0]
+		d.mu.Unlock()
+
+		d.slots <- struct{}{}
+		d.execute(key, task)
+		<-d.slots
+
+		// The task is only removed once it has finished, so the queue depth
+		// includes the running task
+		d.mu.Lock()
+		l.tasks = l.tasks[1:]
+		d.mu.Unlock()
+		d.wg.Done()
+	}
+}
+
+func (d *Dispatcher) execute(key string, task func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			logging.Log.Error().Str("key", key).Interface("panic", v).Bytes("stack", debug.Stack()).Msg("Recovered from panic in task")
+		}
+	}()
+	task()
+}
+
+// Drain stops accepting tasks and waits until queued and running tasks have
+// finished or ctx is done.
+func (d *Dispatcher) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tasks still running: %w", ctx.Err())
+	}
+}
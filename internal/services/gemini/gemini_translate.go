@@ -2,13 +2,17 @@ package gemini
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
 	gemini "github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/gemini/schemas"
 	"github.com/pemistahl/lingua-go"
@@ -53,7 +57,7 @@ func (g *geminiTranslateService) TranslateText(text string, sourceLang lingua.La
 		// If successful, return the result
 		if err == nil {
 			if attempt > 1 {
-				fmt.Printf("Translation succeeded on attempt %d\n", attempt)
+				logging.Log.Info().Int("attempt", attempt).Msg("Translation succeeded after retrying")
 			}
 			return result, nil
 		}
@@ -72,8 +76,7 @@ func (g *geminiTranslateService) TranslateText(text string, sourceLang lingua.La
 			}
 		}
 
-		fmt.Printf("Translation attempt %d failed: %v\n", attempt, err)
-		fmt.Printf("Retrying in %v...\n", backoff)
+		logging.Log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msg("Translation attempt failed, retrying")
 		time.Sleep(backoff)
 	}
 
@@ -173,3 +176,27 @@ func (g *geminiTranslateService) SetTemperature(temp float64) error {
 func (g *geminiTranslateService) GetTemperature() float64 {
 	return g.temperature
 }
+
+func (g *geminiTranslateService) Ping(ctx context.Context) error {
+	endpoint := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s?key=%s", g.modelID, g.geminiAPIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		// Drop the request URL from the error, it carries the API key.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("gemini returned status %d", res.StatusCode)
+	}
+	return nil
+}
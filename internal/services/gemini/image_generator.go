@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
 )
 
@@ -77,7 +78,7 @@ func NewGeminiImageGenerator(model, apiKey string) services.ImageGenerator {
 
 // GenerateImage sends the prompt and returns decoded image bytes.
 func (g *geminiImageGenerator) GenerateImage(ctx context.Context, prompt string) ([]byte, error) {
-	fmt.Printf("Starting image generation with model %s\n", g.modelID)
+	logging.Log.Debug().Str("model", g.modelID).Msg("Starting image generation")
 
 	// ---------- build request body ----------
 	payload := requestPayload{
@@ -103,7 +104,6 @@ func (g *geminiImageGenerator) GenerateImage(ctx context.Context, prompt string)
 	// ---------- HTTP request ----------
 	url := fmt.Sprintf("%s/%s:%s?key=%s",
 		g.geminiAPIBaseURL, g.modelID, g.generateContentAPI, g.apiKey)
-	fmt.Printf("Sending request to %s\n", url)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
@@ -122,7 +122,7 @@ func (g *geminiImageGenerator) GenerateImage(ctx context.Context, prompt string)
 		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBuf))
 	}
 
-	fmt.Println("Received response from Gemini API, extracting image data...")
+	logging.Log.Debug().Msg("Received response from Gemini API, extracting image data")
 
 	// ---------- stream & extract image ----------
 	imageData, err := extractImageData(resp.Body)
@@ -130,13 +130,12 @@ func (g *geminiImageGenerator) GenerateImage(ctx context.Context, prompt string)
 		return nil, fmt.Errorf("extract image data: %w", err)
 	}
 
-	fmt.Println("Successfully extracted image data, decoding base64...")
 	decoded, err := base64.StdEncoding.DecodeString(imageData)
 	if err != nil {
 		return nil, fmt.Errorf("base64-decode image: %w", err)
 	}
 
-	fmt.Printf("Successfully decoded image (%d bytes)\n", len(decoded))
+	logging.Log.Debug().Int("bytes", len(decoded)).Msg("Decoded generated image")
 	return decoded, nil
 }
 
@@ -155,7 +154,7 @@ func extractImageData(r io.Reader) (string, error) {
 		return "", fmt.Errorf("decode events array: %w", err)
 	}
 
-	fmt.Printf("Received %d events from API\n", len(events))
+	logging.Log.Debug().Int("events", len(events)).Msg("Received events from API")
 
 	// Process each event in the array
 	for _, event := range events {
@@ -164,7 +163,6 @@ func extractImageData(r io.Reader) (string, error) {
 				if p.InlineData != nil && p.InlineData.Data != "" {
 					// Append the data chunk
 					imageData.WriteString(p.InlineData.Data)
-					fmt.Printf("Received image data chunk (%d bytes)\n", len(p.InlineData.Data))
 				}
 			}
 		}
@@ -175,6 +173,6 @@ func extractImageData(r io.Reader) (string, error) {
 		return "", errors.New("no inlineData.data found in response")
 	}
 
-	fmt.Printf("Total image data size: %d bytes\n", len(finalData))
+	logging.Log.Debug().Int("bytes", len(finalData)).Msg("Extracted image data")
 	return finalData, nil
 }
@@ -0,0 +1,360 @@
+package messagehandler
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/utility"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/utils"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	settingAfkExcluded = "afk_exclude"
+
+	// maxAfkSenders bounds the senders remembered for the catch-up summary;
+	// messages of further senders are only counted
+	maxAfkSenders = 100
+)
+
+// afkState is the AFK mode of an account. It lives in memory, so a restart
+// ends it.
+type afkState struct {
+	mu      sync.Mutex
+	enabled bool
+	since   time.Time
+	until   time.Time
+	message string
+	timer   *time.Timer
+
+	// lastReply throttles replies per sender, missed collects the summary
+	// and replies caches the custom message per language
+	lastReply map[string]time.Time
+	missed    map[string]*utility.AfkSender
+	others    int
+	replies   map[string]string
+}
+
+// reset ends AFK mode. The caller must hold mu, which is left as is.
+func (afk *afkState) reset() {
+	if afk.timer != nil {
+		afk.timer.Stop()
+	}
+	afk.enabled = false
+	afk.since = time.Time{}
+	afk.until = time.Time{}
+	afk.message = ""
+	afk.timer = nil
+	afk.lastReply = nil
+	afk.missed = nil
+	afk.others = 0
+	afk.replies = nil
+}
+
+// StartAfk implements [utility.AfkManager]. A zero until keeps AFK on until
+// StopAfk; an empty message uses the built-in one. Starting again while AFK
+// replaces the message and expiry but keeps the senders seen so far.
+func (h *WhatsMeowEventHandler) StartAfk(until time.Time, message string) {
+	afk := &h.afk
+	afk.mu.Lock()
+	defer afk.mu.Unlock()
+
+	if !afk.enabled {
+		afk.enabled = true
+		afk.since = time.Now()
+		afk.lastReply = make(map[string]time.Time)
+		afk.missed = make(map[string]*utility.AfkSender)
+		afk.others = 0
+	}
+	afk.until = until
+	afk.message = message
+	afk.replies = make(map[string]string)
+
+	if afk.timer != nil {
+		afk.timer.Stop()
+		afk.timer = nil
+	}
+	if !until.IsZero() {
+		since := afk.since
+		afk.timer = time.AfterFunc(time.Until(until), func() { h.expireAfk(since) })
+	}
+}
+
+// StopAfk implements [utility.AfkManager]. It reports false if AFK wasn't on.
+func (h *WhatsMeowEventHandler) StopAfk() (utility.AfkSummary, bool) {
+	afk := &h.afk
+	afk.mu.Lock()
+	if !afk.enabled {
+		afk.mu.Unlock()
+		return utility.AfkSummary{}, false
+	}
+
+	summary := utility.AfkSummary{Since: afk.since, Others: afk.others}
+	for _, sender := range afk.missed {
+		summary.Senders = append(summary.Senders, *sender)
+	}
+	afk.reset()
+	afk.mu.Unlock()
+
+	sort.Slice(summary.Senders, func(i, j int) bool {
+		return summary.Senders[i].Last.After(summary.Senders[j].Last)
+	})
+	h.nameGroups(summary.Senders)
+	return summary, true
+}
+
+// AfkStatus implements [utility.AfkManager].
+func (h *WhatsMeowEventHandler) AfkStatus() (utility.AfkStatus, bool) {
+	afk := &h.afk
+	afk.mu.Lock()
+	defer afk.mu.Unlock()
+
+	if !afk.enabled {
+		return utility.AfkStatus{}, false
+	}
+	messages := afk.others
+	for _, sender := range afk.missed {
+		messages += sender.Count
+	}
+	return utility.AfkStatus{
+		Since:    afk.since,
+		Until:    afk.until,
+		Message:  afk.message,
+		Senders:  len(afk.missed) + afk.others,
+		Messages: messages,
+	}, true
+}
+
+// SetAfkExcluded implements [utility.AfkManager].
+func (h *WhatsMeowEventHandler) SetAfkExcluded(jid types.JID, excluded bool) error {
+	value := ""
+	if excluded {
+		value = "true"
+	}
+	return h.setSetting(jid.ToNonAD().String(), settingAfkExcluded, value)
+}
+
+// AfkExcluded implements [utility.AfkManager].
+func (h *WhatsMeowEventHandler) AfkExcluded() ([]types.JID, error) {
+	chats, err := h.chatSettings.ChatsWith(context.Background(), h.accountID(), settingAfkExcluded)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.JID, 0, len(chats))
+	for chat := range chats {
+		if jid, err := types.ParseJID(chat); err == nil {
+			result = append(result, jid)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].String() < result[j].String() })
+	return result, nil
+}
+
+// expireAfk ends the AFK mode started at since once it expires and posts the
+// summary to the owner's own chat.
+func (h *WhatsMeowEventHandler) expireAfk(since time.Time) {
+	h.afk.mu.Lock()
+	current := h.afk.enabled && h.afk.since.Equal(since)
+	h.afk.mu.Unlock()
+	if !current {
+		return
+	}
+
+	summary, ok := h.StopAfk()
+	if !ok || h.client.Store.ID == nil {
+		return
+	}
+	owner := h.ownerInfo()
+	l := h.localizer(owner)
+	text := l.T("afk.expired", "⏰ AFK mode ended automatically.") + "\n\n" + utility.AfkSummaryText(l, h.UserLocation(owner), summary)
+	if _, err := h.SendText(context.Background(), owner.Chat, text); err != nil {
+		h.logger().Warn().Err(err).Msg("Failed to send AFK summary")
+	}
+}
+
+// handleAfk answers a non-command message while AFK. Direct messages are
+// answered, group messages only when they mention or reply to the account;
+// each sender is answered at most once per AFK_COOLDOWN, but every message
+// counts towards the summary.
+func (h *WhatsMeowEventHandler) handleAfk(msg *waProto.Message, msgInfo types.MessageInfo, text string) {
+	if msgInfo.IsFromMe || h.client.Store.ID == nil {
+		return
+	}
+	switch msgInfo.Chat.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+	default:
+		// Status updates, newsletters and broadcast lists
+		return
+	}
+
+	h.afk.mu.Lock()
+	enabled := h.afk.enabled
+	h.afk.mu.Unlock()
+	if !enabled {
+		return
+	}
+
+	if msgInfo.IsGroup && !h.isAddressed(msg) {
+		return
+	}
+	if h.afkExcluded(msgInfo) || h.resolveRole(msgInfo) == framework.RoleBanned {
+		return
+	}
+
+	sender := auditSender(msgInfo)
+	if !h.recordAfkMessage(msgInfo, sender) {
+		return
+	}
+
+	_ = NewHandlerAdapter(h).SendResponse(msgInfo, h.afkReply(msgInfo, text))
+}
+
+// afkExcluded reports whether the chat or the sender of msgInfo was excluded
+// with /afk exclude.
+func (h *WhatsMeowEventHandler) afkExcluded(msgInfo types.MessageInfo) bool {
+	excluded, err := h.AfkExcluded()
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Failed to load AFK exclusions")
+		return false
+	}
+	for _, jid := range []types.JID{msgInfo.Chat, msgInfo.Sender, msgInfo.SenderAlt} {
+		if !jid.IsEmpty() && slices.Contains(excluded, jid.ToNonAD()) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordAfkMessage adds the message to the summary and reports whether the
+// sender is due for a reply.
+func (h *WhatsMeowEventHandler) recordAfkMessage(msgInfo types.MessageInfo, sender string) bool {
+	afk := &h.afk
+	afk.mu.Lock()
+	defer afk.mu.Unlock()
+	if !afk.enabled {
+		return false
+	}
+
+	now := time.Now()
+	entry, ok := afk.missed[sender]
+	if !ok && len(afk.missed) >= maxAfkSenders {
+		afk.others++
+	} else {
+		if !ok {
+			entry = &utility.AfkSender{Name: "+" + senderUser(sender)}
+			afk.missed[sender] = entry
+		}
+		if msgInfo.PushName != "" {
+			entry.Name = msgInfo.PushName
+		}
+		entry.Count++
+		entry.Last = now
+		if msgInfo.IsGroup {
+			if !slices.Contains(entry.Groups, msgInfo.Chat.ToNonAD()) {
+				entry.Groups = append(entry.Groups, msgInfo.Chat.ToNonAD())
+			}
+		} else {
+			entry.Direct = true
+		}
+	}
+
+	if last, ok := afk.lastReply[sender]; ok && now.Sub(last) < config.AppConfig.AfkCooldown {
+		return false
+	}
+	afk.lastReply[sender] = now
+	return true
+}
+
+// afkReply returns the AFK message in the language the message was written
+// in, else in the sender's UI language.
+func (h *WhatsMeowEventHandler) afkReply(msgInfo types.MessageInfo, text string) string {
+	l := h.localizer(msgInfo)
+	if text != "" {
+		detected, ok := h.detector.DetectLanguage(text)
+		if _, supported := constants.SupportedLanguages[utils.GetCodeByLang(detected)]; ok && supported {
+			l = l.In(utils.GetCodeByLang(detected))
+		}
+	}
+
+	h.afk.mu.Lock()
+	message, until := h.afk.message, h.afk.until
+	cached, isCached := h.afk.replies[l.Lang()]
+	h.afk.mu.Unlock()
+
+	reply := cached
+	if message == "" {
+		reply = l.T("afk.reply",
+			`The person you are trying to reach is not available at the moment, in case of an urgency - Reach out via call.`)
+	} else if !isCached {
+		reply = h.translateAfkMessage(message, l.Lang())
+	}
+
+	if !until.IsZero() {
+		reply += "\n" + l.T("afk.back_at", "Back around %s.", until.In(h.UserLocation(h.ownerInfo())).Format("Mon 15:04 MST"))
+	}
+	return reply
+}
+
+// translateAfkMessage translates the custom AFK message to lang once per AFK
+// period; the message is sent as written if translation fails.
+func (h *WhatsMeowEventHandler) translateAfkMessage(message, lang string) string {
+	translated := message
+	if source, ok := h.detector.DetectLanguage(message); !ok || utils.GetCodeByLang(source) != lang {
+		result, _, err := h.Translate(context.Background(), message, "", lang)
+		if err != nil {
+			h.logger().Warn().Err(err).Str("lang", lang).Msg("Failed to translate AFK message")
+			return message
+		}
+		translated = result
+	}
+
+	h.afk.mu.Lock()
+	if h.afk.enabled && h.afk.message == message {
+		h.afk.replies[lang] = translated
+	}
+	h.afk.mu.Unlock()
+	return translated
+}
+
+// nameGroups fills in the subjects of the groups senders wrote in.
+func (h *WhatsMeowEventHandler) nameGroups(senders []utility.AfkSender) {
+	names := make(map[types.JID]string)
+	for i := range senders {
+		senders[i].GroupNames = make([]string, len(senders[i].Groups))
+		for j, group := range senders[i].Groups {
+			name, ok := names[group]
+			if !ok {
+				name = group.User
+				if info, err := h.client.GetGroupInfo(context.Background(), group); err == nil && info.Name != "" {
+					name = info.Name
+				}
+				names[group] = name
+			}
+			senders[i].GroupNames[j] = name
+		}
+	}
+}
+
+// ownerInfo describes a message from the account to itself, used to resolve
+// the owner's language and timezone outside of a command.
+func (h *WhatsMeowEventHandler) ownerInfo() types.MessageInfo {
+	own := h.client.Store.ID.ToNonAD()
+	return types.MessageInfo{MessageSource: types.MessageSource{Chat: own, Sender: own, IsFromMe: true}}
+}
+
+// senderUser returns the user part of a sender as stored by auditSender.
+func senderUser(sender string) string {
+	jid, err := types.ParseJID(sender)
+	if err != nil {
+		return sender
+	}
+	return jid.User
+}
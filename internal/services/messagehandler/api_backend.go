@@ -0,0 +1,201 @@
+package messagehandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/server"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/utils"
+	"github.com/pemistahl/lingua-go"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Translate implements [server.APIBackend].
+func (h *WhatsMeowEventHandler) Translate(ctx context.Context, text, source, target string) (string, string, error) {
+	targetLang := utils.GetLangByCode(target)
+	if targetLang == lingua.Unknown {
+		return "", "", fmt.Errorf("%w: %s", server.ErrUnsupportedLanguage, target)
+	}
+
+	var sourceLang lingua.Language
+	if source != "" {
+		if sourceLang = utils.GetLangByCode(source); sourceLang == lingua.Unknown {
+			return "", "", fmt.Errorf("%w: %s", server.ErrUnsupportedLanguage, source)
+		}
+	} else {
+		detected, ok := h.detector.DetectLanguage(text)
+		if !ok {
+			return "", "", fmt.Errorf("could not detect source language")
+		}
+		sourceLang = detected
+	}
+
+	translated, err := h.translator.TranslateText(text, sourceLang, targetLang)
+	if err != nil {
+		return "", "", fmt.Errorf("translation failed: %w", err)
+	}
+	return translated, strings.ToLower(sourceLang.IsoCode639_1().String()), nil
+}
+
+// SendText implements [server.APIBackend].
+func (h *WhatsMeowEventHandler) SendText(ctx context.Context, to types.JID, text string) (types.MessageID, error) {
+	if !h.client.IsLoggedIn() {
+		return "", server.ErrNotLoggedIn
+	}
+
+	resp, err := h.client.SendMessage(ctx, to, &waProto.Message{
+		Conversation: proto.String(text),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// SendMedia implements [server.APIBackend].
+func (h *WhatsMeowEventHandler) SendMedia(ctx context.Context, to types.JID, mediaType framework.MediaType, data []byte, caption, filename string) error {
+	if !h.client.IsLoggedIn() {
+		return server.ErrNotLoggedIn
+	}
+
+	uploader := framework.NewMediaUploader(&ClientAdapter{client: h.client})
+	switch mediaType {
+	case framework.MediaImage:
+		return uploader.UploadAndSendImage(ctx, to, data, caption)
+	case framework.MediaVideo:
+		return uploader.UploadAndSendVideo(ctx, to, data, caption)
+	case framework.MediaAudio:
+		return uploader.UploadAndSendAudio(ctx, to, data, "", false)
+	default:
+		if filename == "" {
+			filename = fmt.Sprintf("document_%d", time.Now().Unix())
+		}
+		return uploader.UploadAndSendDocument(ctx, to, data, filename, caption)
+	}
+}
+
+// ExecuteCommand implements [server.APIBackend]. The command runs as if the
+// account owner had typed it in chat (the self chat when chat is empty), but
+// text responses are captured instead of being sent to WhatsApp. If the
+// command starts a job, ExecuteCommand waits for it under ctx so the final
+// status of the job is among the responses.
+func (h *WhatsMeowEventHandler) ExecuteCommand(ctx context.Context, chat types.JID, name string, args string) ([]string, error) {
+	if h.client.Store.ID == nil {
+		return nil, server.ErrNotLoggedIn
+	}
+
+	name = strings.ToLower(strings.TrimPrefix(name, "/"))
+	cmd, exists := h.commandRegistry.Get(name)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", server.ErrCommandNotFound, name)
+	}
+
+	self := h.client.Store.ID.ToNonAD()
+	if chat.IsEmpty() {
+		chat = self
+	}
+
+	text := "/" + name
+	if args != "" {
+		text += " " + args
+	}
+
+	cmdArgs, flags, err := framework.ParseArgs(args, cmd.Metadata())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", server.ErrInvalidArguments, err)
+	}
+
+	recorder := &recordingAdapter{HandlerAdapter: NewHandlerAdapter(h)}
+	cmdCtx := &framework.Context{
+		Context: ctx,
+		Message: &waProto.Message{Conversation: proto.String(text)},
+		MessageInfo: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     chat,
+				Sender:   self,
+				IsFromMe: true,
+				IsGroup:  chat.Server == types.GroupServer,
+			},
+			ID:        h.client.GenerateMessageID(),
+			Timestamp: time.Now(),
+		},
+		Command: name,
+		Args:    cmdArgs,
+		RawArgs: args,
+		Flags:   flags,
+		Role:    framework.RoleOwner,
+		Handler: recorder,
+		Jobs:    h.jobs,
+	}
+	cmdCtx.Logger = h.commandLogger(name, cmdCtx.MessageInfo).With().Str("source", "api").Logger()
+	cmdCtx.Locale = h.localizer(cmdCtx.MessageInfo)
+
+	started := time.Now()
+	err = cmd.Execute(cmdCtx)
+	h.recordCommand(cmdCtx, cmd.Metadata().Name, started, err)
+	if err != nil {
+		return recorder.Responses(), fmt.Errorf("command %s failed: %w", name, err)
+	}
+
+	if job, ok := h.jobs.ByMessage(chat, cmdCtx.MessageInfo.ID); ok {
+		select {
+		case <-job.Done():
+		case <-ctx.Done():
+			return recorder.Responses(), fmt.Errorf("command %s is still running as job #%s: %w", name, job.ID, ctx.Err())
+		}
+	}
+	return recorder.Responses(), nil
+}
+
+// recordingAdapter captures text responses and edits instead of delivering
+// them, so API callers receive command output in the HTTP response. Media is
+// still sent to the target chat.
+type recordingAdapter struct {
+	*HandlerAdapter
+
+	mu        sync.Mutex
+	responses []string
+}
+
+func (r *recordingAdapter) record(text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, text)
+}
+
+func (r *recordingAdapter) Responses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.responses...)
+}
+
+func (r *recordingAdapter) SendResponse(msgInfo types.MessageInfo, text string) error {
+	r.record(text)
+	return nil
+}
+
+func (r *recordingAdapter) SendResponseWithID(msgInfo types.MessageInfo, text string) (types.MessageID, error) {
+	r.record(text)
+	return msgInfo.ID, nil
+}
+
+func (r *recordingAdapter) EditMessage(msgInfo types.MessageInfo, newText string) error {
+	r.record(newText)
+	return nil
+}
+
+func (r *recordingAdapter) EditMessageWithOriginal(msgInfo types.MessageInfo, newText string, originalMsg *waProto.Message) error {
+	r.record(newText)
+	return nil
+}
+
+func (r *recordingAdapter) SendTranslation(msgInfo types.MessageInfo, sourceID types.MessageID, targetLang, text string) error {
+	r.record(text)
+	return nil
+}
@@ -0,0 +1,154 @@
+package messagehandler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/admin"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"github.com/rs/zerolog"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxAuditArgs caps the stored arguments; they are only meant to identify
+// the invocation.
+const maxAuditArgs = 100
+
+// logger returns the root logger tagged with the account.
+func (h *WhatsMeowEventHandler) logger() *zerolog.Logger {
+	logger := logging.Log.With().Str("account", h.accountID()).Logger()
+	return &logger
+}
+
+// commandLogger is the logger handed to a command through its context.
+func (h *WhatsMeowEventHandler) commandLogger(command string, msgInfo types.MessageInfo) zerolog.Logger {
+	return logging.Log.With().
+		Str("account", h.accountID()).
+		Str("command", command).
+		Str("chat", msgInfo.Chat.String()).
+		Str("sender", auditSender(msgInfo)).
+		Logger()
+}
+
+// auditSender prefers the phone number of senders that are addressed by LID,
+// so /audit --user finds them.
+func auditSender(msgInfo types.MessageInfo) string {
+	if msgInfo.Sender.Server == types.HiddenUserServer && !msgInfo.SenderAlt.IsEmpty() {
+		return msgInfo.SenderAlt.ToNonAD().String()
+	}
+	return msgInfo.Sender.ToNonAD().String()
+}
+
+// auditOutcome classifies the result of a command or job.
+func auditOutcome(err error) string {
+	var panicErr *framework.PanicError
+	var argErr *framework.ArgError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, framework.ErrPermissionDenied):
+		return "denied"
+	case errors.As(err, &argErr):
+		return "invalid_args"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, framework.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, framework.ErrTimeout):
+		return "timeout"
+	case errors.As(err, &panicErr):
+		return "panic"
+	default:
+		return "error"
+	}
+}
+
+// recordCommand logs a finished command and adds it to the audit log under
+// its canonical name. A command that started a job is recorded as queued;
+// recordJob adds a second entry once the job has ended.
+func (h *WhatsMeowEventHandler) recordCommand(ctx *framework.Context, name string, started time.Time, err error) {
+	outcome := auditOutcome(err)
+	if err == nil {
+		if _, running := h.jobs.ByMessage(ctx.MessageInfo.Chat, ctx.MessageInfo.ID); running {
+			outcome = "queued"
+		}
+	}
+	h.recordAudit(ctx, name, started, outcome, err)
+}
+
+// recordJob adds the result of a finished job to the audit log, timed from
+// when it was started.
+func (h *WhatsMeowEventHandler) recordJob(job *framework.Job) {
+	ctx := job.Origin()
+	name := job.Command
+	if cmd, exists := h.commandRegistry.Get(job.Command); exists {
+		name = cmd.Metadata().Name
+	}
+	ctx.Logger = ctx.Logger.With().Str("job", job.ID).Logger()
+	h.recordAudit(ctx, name, job.StartedAt, auditOutcome(job.Err()), job.Err())
+}
+
+func (h *WhatsMeowEventHandler) recordAudit(ctx *framework.Context, name string, started time.Time, outcome string, err error) {
+	duration := time.Since(started)
+
+	event := ctx.Logger.Info()
+	if outcome == "error" || outcome == "panic" || outcome == "timeout" {
+		event = ctx.Logger.Warn().Err(err)
+	}
+	event.Str("outcome", outcome).Dur("duration", duration).Msg("Command finished")
+
+	// Redacted before shortening, so no part of a secret is kept
+	args := logging.RedactSecrets(ctx.RawArgs)
+	if len(args) > maxAuditArgs {
+		args = strings.ToValidUTF8(args[:maxAuditArgs], "") + "…"
+	}
+	entry := storage.AuditEntry{
+		Timestamp: started,
+		Chat:      ctx.MessageInfo.Chat.String(),
+		Sender:    auditSender(ctx.MessageInfo),
+		Role:      ctx.Role.String(),
+		Command:   name,
+		Args:      args,
+		Outcome:   outcome,
+		Duration:  duration,
+	}
+	if err != nil {
+		entry.Error = logging.RedactSecrets(err.Error())
+	}
+	if err := h.audit.Record(context.Background(), h.accountID(), entry); err != nil {
+		ctx.Logger.Error().Err(err).Msg("Failed to record audit entry")
+	}
+}
+
+// AuditEntries implements [admin.AuditLog].
+func (h *WhatsMeowEventHandler) AuditEntries(filter admin.AuditFilter) ([]admin.AuditEntry, error) {
+	entries, err := h.audit.Query(context.Background(), h.accountID(), storage.AuditQuery{
+		Chat:    filter.Chat,
+		Sender:  filter.Sender,
+		Command: filter.Command,
+		Outcome: filter.Outcome,
+		Limit:   filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]admin.AuditEntry, len(entries))
+	for i, e := range entries {
+		result[i] = admin.AuditEntry{
+			Time:     e.Timestamp,
+			Chat:     e.Chat,
+			Sender:   e.Sender,
+			Command:  e.Command,
+			Args:     e.Args,
+			Outcome:  e.Outcome,
+			Error:    e.Error,
+			Duration: e.Duration,
+		}
+	}
+	return result, nil
+}
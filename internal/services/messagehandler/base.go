@@ -2,13 +2,17 @@ package messagehandler
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"sync"
+	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/utility"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/dispatcher"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/memegenerator"
-	"github.com/mdp/qrterminal/v3"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -20,10 +24,38 @@ type WhatsMeowEventHandler struct {
 	imageGenerator  services.ImageGenerator
 	memeGenerator   *memegenerator.MemeGenerator
 	commandRegistry *framework.Registry
-	isAfkMode       bool
+	translations    *storage.TranslationStore
+	messages        *storage.MessageStore
+	roles           *storage.RoleStore
+	adminCache      groupAdminCache
+	chatSettings    *storage.ChatSettingsStore
+	rateLimits      *storage.RateLimitStore
+	audit           *storage.AuditStore
+	uiStrings       *storage.UIStringStore
+	catalog         *i18n.Catalog
+	schedules       *storage.ScheduleStore
+	stopScheduler   context.CancelFunc
+	rules           *storage.RuleStore
+	ruleCache       ruleCache
+	downloads       *storage.DownloadStore
+	downloader      *utility.DownloadCommand
+	resumeDownloads sync.Once
+	limiter         *framework.RateLimiter
+	dispatcher      *dispatcher.Dispatcher
+	jobs            *framework.JobManager
+	backpressure    backpressureNotices
+	errorReports    errorReports
+	afk             afkState
+
+	stateMu           sync.RWMutex
+	loggedOut         bool
+	lastEventAt       time.Time
+	disconnectedSince time.Time
+
+	pairing pairingState
 }
 
-func NewWhatsMeowEventHandler(client *whatsmeow.Client, detector services.LangDetectService, translator services.TranslateService, imageGenerator services.ImageGenerator) (*WhatsMeowEventHandler, error) {
+func NewWhatsMeowEventHandler(client *whatsmeow.Client, detector services.LangDetectService, translator services.TranslateService, imageGenerator services.ImageGenerator, db *storage.DB, dispatcher *dispatcher.Dispatcher) (*WhatsMeowEventHandler, error) {
 	handler := &WhatsMeowEventHandler{
 		client:          client,
 		detector:        detector,
@@ -31,53 +63,83 @@ func NewWhatsMeowEventHandler(client *whatsmeow.Client, detector services.LangDe
 		imageGenerator:  imageGenerator,
 		memeGenerator:   memegenerator.NewMemeGenerator(),
 		commandRegistry: framework.NewRegistry(),
+		translations:    db.Translations(),
+		messages:        db.Messages(),
+		roles:           db.Roles(),
+		chatSettings:    db.ChatSettings(),
+		rateLimits:      db.RateLimits(),
+		audit:           db.Audit(),
+		uiStrings:       db.UIStrings(),
+		schedules:       db.Schedules(),
+		rules:           db.Rules(),
+		downloads:       db.Downloads(),
+		dispatcher:      dispatcher,
+	}
+
+	handler.jobs = framework.NewJobManager(handler)
+	handler.jobs.OnFinish(handler.recordJob)
+	handler.catalog = i18n.NewCatalog(handler.translateUIText, handler)
+
+	if config.AppConfig.PersistRateLimits {
+		handler.limiter = framework.NewRateLimiter(handler)
+	} else {
+		handler.limiter = framework.NewRateLimiter(nil)
 	}
 
 	// Initialize all commands
 	if err := handler.InitializeCommands(); err != nil {
 		return nil, err
 	}
+	return handler, nil
+}
 
-	if handler.client.Store.ID == nil {
-		if err := handler.setupQRLogin(); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := client.Connect(); err != nil {
-			return nil, err
-		}
+// Start subscribes the handler to client events, starts the scheduler and
+// either connects the client or, for a device that was never linked, starts
+// pairing.
+func (h *WhatsMeowEventHandler) Start() error {
+	h.client.AddEventHandler(h.HandleEvents)
+
+	schedulerCtx, cancel := context.WithCancel(context.Background())
+	h.stopScheduler = cancel
+	go h.runScheduler(schedulerCtx)
+
+	if h.client.Store.ID == nil {
+		h.startPairing()
+		return nil
 	}
-	return handler, nil
+	return h.client.Connect()
 }
 
 func (h *WhatsMeowEventHandler) HandleEvents(evt any) {
+	h.trackConnectionEvent(evt)
+
 	switch v := evt.(type) {
 	case *events.Message:
-		h.handleMessage(v.Message, v.Info)
+		h.dispatchMessage(v)
+	case *events.Connected:
+		h.resumeDownloads.Do(func() { go h.resumeSavedDownloads() })
+	case *events.GroupInfo:
+		h.adminCache.invalidate(v.JID)
+	case *events.LoggedOut:
+		go h.handleLoggedOut()
 	}
 }
 
-func (h *WhatsMeowEventHandler) SetAfkMode(enabled bool) {
-	h.isAfkMode = enabled
+// accountID identifies this account in the bot database. It is empty until
+// the device has been linked.
+func (h *WhatsMeowEventHandler) accountID() string {
+	if h.client.Store.ID == nil {
+		return ""
+	}
+	return h.client.Store.ID.User
 }
 
-func (h *WhatsMeowEventHandler) IsAfkMode() bool {
-	return h.isAfkMode
+// CancelJobs cancels every running job of the account, e.g. on shutdown.
+func (h *WhatsMeowEventHandler) CancelJobs() {
+	h.jobs.CancelAll()
 }
 
-func (h *WhatsMeowEventHandler) setupQRLogin() error {
-	qrChan, _ := h.client.GetQRChannel(context.Background())
-	err := h.client.Connect()
-	if err != nil {
-		return err
-	}
-
-	for evt := range qrChan {
-		if evt.Event == "code" {
-			qrterminal.GenerateHalfBlock(evt.Code, qrterminal.L, os.Stdout)
-		} else {
-			fmt.Println("Login event:", evt.Event)
-		}
-	}
-	return nil
+// WaitJobs waits until the account's jobs have finished or ctx is done.
+func (h *WhatsMeowEventHandler) WaitJobs(ctx context.Context) error {
+	return h.jobs.Wait(ctx)
 }
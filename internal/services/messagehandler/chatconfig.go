@@ -0,0 +1,123 @@
+package messagehandler
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	settingAllowed       = "allowed"
+	settingAllowlistMode = "allowlist_mode"
+)
+
+// CommandAllowed implements [handlers.CommandFilter]. It decides whether a
+// command may run in the chat of msgInfo. The owner is never restricted; in
+// allowlist mode nobody else gets answers outside allowlisted chats, and
+// essential commands can't be disabled.
+func (h *WhatsMeowEventHandler) CommandAllowed(msgInfo types.MessageInfo, meta *framework.Metadata, role framework.Role) bool {
+	if role >= framework.RoleOwner {
+		return true
+	}
+	if !h.chatAllowed(msgInfo, role) {
+		return false
+	}
+	if meta.Essential {
+		return true
+	}
+
+	chat := msgInfo.Chat.ToNonAD()
+	disabled, err := h.DisabledCommands(chat)
+	if err != nil {
+		h.logger().Error().Err(err).Str("chat", chat.String()).Msg("Failed to load disabled commands")
+		return true
+	}
+	return !slices.Contains(disabled, strings.ToLower(meta.Name)) &&
+		!slices.Contains(disabled, strings.ToLower(meta.Category))
+}
+
+// chatAllowed reports whether the bot answers role in the chat of msgInfo,
+// i.e. allowlist mode is off, the chat is allowlisted or role is the owner.
+func (h *WhatsMeowEventHandler) chatAllowed(msgInfo types.MessageInfo, role framework.Role) bool {
+	if role >= framework.RoleOwner {
+		return true
+	}
+
+	enabled, err := h.AllowlistMode()
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Failed to load allowlist mode")
+		return true
+	}
+	if !enabled {
+		return true
+	}
+	allowed, err := h.IsChatAllowed(msgInfo.Chat.ToNonAD())
+	return err == nil && allowed
+}
+
+// DisableCommand implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) DisableCommand(chat types.JID, name string) error {
+	return h.chatSettings.Disable(context.Background(), h.accountID(), chat.ToNonAD().String(), strings.ToLower(name))
+}
+
+// EnableCommand implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) EnableCommand(chat types.JID, name string) (bool, error) {
+	return h.chatSettings.Enable(context.Background(), h.accountID(), chat.ToNonAD().String(), strings.ToLower(name))
+}
+
+// DisabledCommands implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) DisabledCommands(chat types.JID) ([]string, error) {
+	return h.chatSettings.Disabled(context.Background(), h.accountID(), chat.ToNonAD().String())
+}
+
+// SetChatAllowed implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) SetChatAllowed(chat types.JID, allowed bool) error {
+	ctx := context.Background()
+	if !allowed {
+		return h.chatSettings.Delete(ctx, h.accountID(), chat.ToNonAD().String(), settingAllowed)
+	}
+	return h.chatSettings.Set(ctx, h.accountID(), chat.ToNonAD().String(), settingAllowed, "true")
+}
+
+// IsChatAllowed implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) IsChatAllowed(chat types.JID) (bool, error) {
+	_, ok, err := h.chatSettings.Get(context.Background(), h.accountID(), chat.ToNonAD().String(), settingAllowed)
+	return ok, err
+}
+
+// AllowedChats implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) AllowedChats() ([]types.JID, error) {
+	chats, err := h.chatSettings.ChatsWith(context.Background(), h.accountID(), settingAllowed)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.JID, 0, len(chats))
+	for chat := range chats {
+		if jid, err := types.ParseJID(chat); err == nil {
+			result = append(result, jid)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].String() < result[j].String() })
+	return result, nil
+}
+
+// SetAllowlistMode implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) SetAllowlistMode(enabled bool) error {
+	ctx := context.Background()
+	if !enabled {
+		return h.chatSettings.Delete(ctx, h.accountID(), storage.GlobalChat, settingAllowlistMode)
+	}
+	return h.chatSettings.Set(ctx, h.accountID(), storage.GlobalChat, settingAllowlistMode, "true")
+}
+
+// AllowlistMode implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) AllowlistMode() (bool, error) {
+	_, ok, err := h.chatSettings.Get(context.Background(), h.accountID(), storage.GlobalChat, settingAllowlistMode)
+	return ok, err
+}
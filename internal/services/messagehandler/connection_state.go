@@ -0,0 +1,54 @@
+package messagehandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/server"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// trackConnectionEvent records connection lifecycle events so the health
+// endpoints can tell a healthy session from a stuck or logged-out one.
+func (h *WhatsMeowEventHandler) trackConnectionEvent(evt any) {
+	h.stateMu.Lock()
+	defer h.stateMu.Unlock()
+
+	h.lastEventAt = time.Now()
+
+	switch v := evt.(type) {
+	case *events.Connected:
+		h.loggedOut = false
+		h.disconnectedSince = time.Time{}
+	case *events.Disconnected:
+		if h.disconnectedSince.IsZero() {
+			h.disconnectedSince = time.Now()
+		}
+	case *events.LoggedOut:
+		h.logger().Warn().Bool("on_connect", v.OnConnect).Str("reason", v.Reason.String()).Msg("Logged out from WhatsApp")
+		h.loggedOut = true
+		if h.disconnectedSince.IsZero() {
+			h.disconnectedSince = time.Now()
+		}
+	}
+}
+
+// ConnectionState implements [server.HealthSource].
+func (h *WhatsMeowEventHandler) ConnectionState() server.ConnectionState {
+	h.stateMu.RLock()
+	defer h.stateMu.RUnlock()
+
+	return server.ConnectionState{
+		Connected:         h.client.IsConnected(),
+		LoggedIn:          h.client.IsLoggedIn(),
+		LoggedOut:         h.loggedOut,
+		Pairing:           h.isPairing(),
+		LastEventAt:       h.lastEventAt,
+		DisconnectedSince: h.disconnectedSince,
+	}
+}
+
+// ProbeTranslator implements [server.HealthSource].
+func (h *WhatsMeowEventHandler) ProbeTranslator(ctx context.Context) error {
+	return h.translator.Ping(ctx)
+}
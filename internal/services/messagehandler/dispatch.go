@@ -0,0 +1,73 @@
+package messagehandler
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/dispatcher"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// backpressureInterval limits how often a chat is told that its queue is
+// full, so that the notice doesn't add to the flood.
+const backpressureInterval = 30 * time.Second
+
+type backpressureNotices struct {
+	mu   sync.Mutex
+	sent map[types.JID]time.Time
+}
+
+// allow reports whether a notice may be sent to chat now and records it.
+func (b *backpressureNotices) allow(chat types.JID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Since(b.sent[chat]) < backpressureInterval {
+		return false
+	}
+	if b.sent == nil {
+		b.sent = make(map[types.JID]time.Time)
+	}
+	b.sent[chat] = time.Now()
+	return true
+}
+
+// dispatchMessage hands a message to the dispatcher so that slow commands
+// don't block whatsmeow's event loop. Messages of one chat are handled in
+// order; different chats are handled in parallel.
+func (h *WhatsMeowEventHandler) dispatchMessage(evt *events.Message) {
+	chat := evt.Info.Chat.ToNonAD()
+	err := h.dispatcher.Submit(h.accountID()+"|"+chat.String(), func() {
+		h.processMessage(evt.Message, evt.Info)
+	})
+
+	switch {
+	case errors.Is(err, dispatcher.ErrQueueFull):
+		// Only commands get a notice; other messages are dropped quietly
+		if strings.HasPrefix(extractText(evt.Message), "/") && h.backpressure.allow(chat) {
+			go h.SendResponse(evt.Info, framework.Warning(h.localizer(evt.Info).T("dispatch.busy",
+				"Too many commands are waiting in this chat, please try again in a moment")))
+		}
+	case errors.Is(err, dispatcher.ErrClosed):
+		// Shutting down
+	case err != nil:
+		h.logger().Error().Err(err).Str("message", evt.Info.ID).Msg("Failed to dispatch message")
+	}
+}
+
+func (h *WhatsMeowEventHandler) processMessage(msg *waProto.Message, msgInfo types.MessageInfo) {
+	switch {
+	case msg.GetProtocolMessage() != nil:
+		h.handleProtocolMessage(msg.GetProtocolMessage(), msgInfo)
+	case msg.GetReactionMessage() != nil:
+		h.handleReaction(msg.GetReactionMessage(), msgInfo)
+	default:
+		h.storeMessage(msg, msgInfo)
+		h.handleMessage(msg, msgInfo)
+	}
+}
@@ -0,0 +1,114 @@
+package messagehandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/utility"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// SaveDownload implements [utility.DownloadStore].
+func (h *WhatsMeowEventHandler) SaveDownload(msgInfo types.MessageInfo, req utility.DownloadRequest) (int64, error) {
+	request, err := json.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode download: %w", err)
+	}
+	return h.downloads.Add(context.Background(), h.accountID(), storage.Download{
+		Chat:      msgInfo.Chat.ToNonAD().String(),
+		Sender:    msgInfo.Sender.ToNonAD().String(),
+		SenderAlt: msgInfo.SenderAlt.ToNonAD().String(),
+		FromMe:    msgInfo.IsFromMe,
+		MessageID: msgInfo.ID,
+		Request:   string(request),
+	})
+}
+
+// SetDownloadStatus implements [utility.DownloadStore].
+func (h *WhatsMeowEventHandler) SetDownloadStatus(id int64, statusID types.MessageID) error {
+	return h.downloads.SetStatus(context.Background(), h.accountID(), id, statusID)
+}
+
+// DeleteDownload implements [utility.DownloadStore].
+func (h *WhatsMeowEventHandler) DeleteDownload(id int64) error {
+	return h.downloads.Delete(context.Background(), h.accountID(), id)
+}
+
+// resumeSavedDownloads queues the downloads that were queued or running when
+// the bot last stopped again, in their original order. Downloads of senders
+// banned meanwhile, or that can't be read, are dropped.
+func (h *WhatsMeowEventHandler) resumeSavedDownloads() {
+	saved, err := h.downloads.List(context.Background(), h.accountID())
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Failed to load saved downloads")
+		return
+	}
+
+	for _, dl := range saved {
+		logger := h.logger().With().Int64("download", dl.ID).Str("chat", dl.Chat).Logger()
+		if err := h.resumeDownload(dl); err != nil {
+			logger.Warn().Err(err).Msg("Dropping saved download")
+			_ = h.DeleteDownload(dl.ID)
+			continue
+		}
+		logger.Info().Msg("Resumed download")
+	}
+}
+
+func (h *WhatsMeowEventHandler) resumeDownload(dl storage.Download) error {
+	var req utility.DownloadRequest
+	if err := json.Unmarshal([]byte(dl.Request), &req); err != nil {
+		return fmt.Errorf("failed to decode download: %w", err)
+	}
+	chat, err := types.ParseJID(dl.Chat)
+	if err != nil {
+		return fmt.Errorf("invalid chat: %w", err)
+	}
+	sender, err := types.ParseJID(dl.Sender)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	var senderAlt types.JID
+	if dl.SenderAlt != "" {
+		if senderAlt, err = types.ParseJID(dl.SenderAlt); err != nil {
+			return fmt.Errorf("invalid sender: %w", err)
+		}
+	}
+
+	msgInfo := types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:      chat,
+			Sender:    sender,
+			SenderAlt: senderAlt,
+			IsFromMe:  dl.FromMe,
+			IsGroup:   chat.Server == types.GroupServer,
+		},
+		ID:        dl.MessageID,
+		Timestamp: dl.CreatedAt,
+	}
+	role := h.resolveRole(msgInfo)
+	if role == framework.RoleBanned {
+		return fmt.Errorf("sender is banned")
+	}
+
+	name := h.downloader.Metadata().Name
+	ctx := &framework.Context{
+		Context:     context.Background(),
+		Message:     &waProto.Message{Conversation: proto.String("/" + name + " " + req.URL)},
+		MessageInfo: msgInfo,
+		Command:     name,
+		Args:        []string{req.URL},
+		RawArgs:     req.URL,
+		Role:        role,
+		Logger:      h.commandLogger(name, msgInfo),
+		Locale:      h.localizer(msgInfo),
+		Handler:     NewHandlerAdapter(h),
+		Jobs:        h.jobs,
+	}
+	return h.downloader.Resume(ctx, dl.ID, req, dl.StatusID)
+}
@@ -0,0 +1,135 @@
+package messagehandler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// sendTranslation delivers a translation the same way SendResponse does and
+// remembers which message it belongs to, so later edits of sourceID can be
+// re-translated.
+func (h *WhatsMeowEventHandler) sendTranslation(msgInfo types.MessageInfo, sourceID types.MessageID, targetLang, text string) error {
+	var translationID types.MessageID
+	if msgInfo.IsFromMe {
+		if err := h.editMessageContent(msgInfo.Chat, msgInfo.ID, text, nil); err != nil {
+			return err
+		}
+		translationID = msgInfo.ID
+	} else {
+		id, err := h.sendReplyMessage(msgInfo.Chat, text, msgInfo.ID)
+		if err != nil {
+			return err
+		}
+		translationID = id
+	}
+
+	// An inline command of our own is replaced by its translation, so there is
+	// no separate source left to follow.
+	if translationID == sourceID {
+		return nil
+	}
+
+	err := h.translations.Save(context.Background(), h.accountID(), storage.Translation{
+		Chat:          msgInfo.Chat.String(),
+		SourceID:      sourceID,
+		TranslationID: translationID,
+		TargetLang:    targetLang,
+		Inline:        sourceID == msgInfo.ID,
+	})
+	if err != nil {
+		h.logger().Error().Err(err).Str("source", sourceID).Msg("Failed to remember translation")
+	}
+	return nil
+}
+
+// handleProtocolMessage reacts to edits and deletions of messages the bot has
+// translated.
+func (h *WhatsMeowEventHandler) handleProtocolMessage(msg *waProto.ProtocolMessage, msgInfo types.MessageInfo) {
+	targetID := msg.GetKey().GetID()
+	if targetID == "" {
+		return
+	}
+
+	switch msg.GetType() {
+	case waProto.ProtocolMessage_MESSAGE_EDIT:
+		text := extractText(msg.GetEditedMessage())
+		if err := h.messages.UpdateText(context.Background(), h.accountID(), msgInfo.Chat.String(), targetID, text); err != nil {
+			h.logger().Error().Err(err).Str("message", targetID).Msg("Failed to update stored message")
+		}
+		h.retranslate(msgInfo.Chat, targetID, text)
+	case waProto.ProtocolMessage_REVOKE:
+		if err := h.messages.Delete(context.Background(), h.accountID(), msgInfo.Chat.String(), targetID); err != nil {
+			h.logger().Error().Err(err).Str("message", targetID).Msg("Failed to delete stored message")
+		}
+		if config.AppConfig.RevokeTranslations {
+			h.revokeTranslations(msgInfo.Chat, targetID)
+		}
+	}
+}
+
+func (h *WhatsMeowEventHandler) retranslate(chat types.JID, sourceID, text string) {
+	ctx := context.Background()
+	account := h.accountID()
+
+	// Our own edits of translations come back as edit events as well.
+	if ok, err := h.translations.IsTranslation(ctx, account, chat.String(), sourceID); err != nil || ok {
+		return
+	}
+
+	records, err := h.translations.BySource(ctx, account, chat.String(), sourceID)
+	if err != nil {
+		h.logger().Error().Err(err).Str("source", sourceID).Msg("Failed to look up translations")
+		return
+	}
+
+	for _, record := range records {
+		sourceText := text
+		if record.Inline {
+			// "/hi some text" - drop the command, keep the text
+			parts := strings.SplitN(strings.TrimSpace(text), " ", 2)
+			if len(parts) < 2 || !strings.HasPrefix(parts[0], "/") {
+				continue
+			}
+			sourceText = parts[1]
+		}
+		if strings.TrimSpace(sourceText) == "" {
+			continue
+		}
+
+		translated, _, err := h.Translate(ctx, sourceText, "", record.TargetLang)
+		if err != nil {
+			h.logger().Warn().Err(err).Str("source", sourceID).Msg("Re-translation failed")
+			continue
+		}
+
+		if err := h.editMessageContent(chat, record.TranslationID, translated, nil); err != nil {
+			h.logger().Error().Err(err).Str("translation", record.TranslationID).Msg("Failed to update translation")
+		}
+	}
+}
+
+func (h *WhatsMeowEventHandler) revokeTranslations(chat types.JID, sourceID string) {
+	ctx := context.Background()
+	account := h.accountID()
+
+	records, err := h.translations.BySource(ctx, account, chat.String(), sourceID)
+	if err != nil || len(records) == 0 {
+		return
+	}
+
+	for _, record := range records {
+		revoke := h.client.BuildRevoke(chat, types.EmptyJID, record.TranslationID)
+		if _, err := h.client.SendMessage(ctx, chat, revoke); err != nil {
+			h.logger().Error().Err(err).Str("translation", record.TranslationID).Msg("Failed to delete translation")
+		}
+	}
+
+	if err := h.translations.DeleteBySource(ctx, account, chat.String(), sourceID); err != nil {
+		h.logger().Error().Err(err).Str("source", sourceID).Msg("Failed to forget translations")
+	}
+}
@@ -0,0 +1,102 @@
+package messagehandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxReportStack bounds the stack trace included in error reports,
+	// keeping them readable on a phone.
+	maxReportStack = 3000
+
+	// errorReportInterval limits how often the owner hears about the same
+	// command failing in the same chat.
+	errorReportInterval = 10 * time.Minute
+)
+
+type errorReports struct {
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// allow reports whether a failure of command in chat may be reported now
+// and records it.
+func (r *errorReports) allow(command string, chat fmt.Stringer) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := command + "|" + chat.String()
+	if time.Since(r.sent[key]) < errorReportInterval {
+		return false
+	}
+	if r.sent == nil {
+		r.sent = make(map[string]time.Time)
+	}
+	for other, at := range r.sent {
+		if time.Since(at) >= errorReportInterval {
+			delete(r.sent, other)
+		}
+	}
+	r.sent[key] = time.Now()
+	return true
+}
+
+// ReportCommandError implements framework.ErrorReporter by sending the details
+// of a panicked or timed out command, or of a failed job, to the owner's own
+// chat ("Message yourself"), at most once per errorReportInterval for a
+// command and chat. Other command failures are only logged; they are usually
+// the user's doing.
+func (h *WhatsMeowEventHandler) ReportCommandError(ctx *framework.Context, err error) {
+	var panicErr *framework.PanicError
+	var jobErr *framework.JobError
+	isPanic := errors.As(err, &panicErr)
+	switch {
+	case errors.As(err, &jobErr):
+		// Already logged by the job manager
+	case isPanic:
+		ctx.Logger.Error().Interface("panic", panicErr.Value).Bytes("stack", panicErr.Stack).Msg("Command panicked")
+	case errors.Is(err, framework.ErrTimeout):
+		ctx.Logger.Warn().Err(err).Msg("Command timed out")
+	default:
+		ctx.Logger.Warn().Err(err).Msg("Command failed")
+		return
+	}
+
+	if h.client.Store.ID == nil || !h.errorReports.allow(ctx.Command, ctx.MessageInfo.Chat.ToNonAD()) {
+		return
+	}
+
+	builder := framework.NewResponseBuilder().AddHeading("🚨 Command failed")
+	builder.AddLine(fmt.Sprintf("*Command:* /%s", ctx.Command))
+	if ctx.RawArgs != "" {
+		builder.AddLine(fmt.Sprintf("*Arguments:* %s", ctx.RawArgs))
+	}
+	builder.AddLine(fmt.Sprintf("*Chat:* %s", ctx.MessageInfo.Chat))
+	builder.AddLine(fmt.Sprintf("*Sender:* %s (%s)", ctx.MessageInfo.Sender.ToNonAD(), ctx.Role))
+	builder.AddLine(fmt.Sprintf("*Time:* %s", time.Now().Format(time.RFC3339)))
+	builder.AddLine(fmt.Sprintf("*Error:* %v", err))
+
+	if panicErr != nil {
+		stack := string(panicErr.Stack)
+		if len(stack) > maxReportStack {
+			stack = stack[:maxReportStack] + "\n..."
+		}
+		builder.AddEmptyLine().AddCodeBlock(stack)
+	}
+
+	owner := h.client.Store.ID.ToNonAD()
+	_, sendErr := h.client.SendMessage(context.Background(), owner, &waProto.Message{
+		Conversation: proto.String(builder.Build()),
+	})
+	if sendErr != nil {
+		ctx.Logger.Error().Err(sendErr).Msg("Failed to send error report")
+	}
+}
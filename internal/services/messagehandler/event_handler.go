@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
@@ -23,6 +24,8 @@ func (h *WhatsMeowEventHandler) handleMessage(msg *waProto.Message, msgInfo type
 	var args []string
 	var rawArgs string
 
+	// Only s/ starts a bare sed script, so chat such as "s, yes, no," isn't
+	// taken for one; other delimiters need /s, e.g. /s s|a/b|c|
 	if strings.HasPrefix(text, "s/") {
 		cmdName = "s"
 		rawArgs = text
@@ -33,17 +36,13 @@ func (h *WhatsMeowEventHandler) handleMessage(msg *waProto.Message, msgInfo type
 			return
 		}
 		cmdName = strings.TrimPrefix(parts[0], "/")
-		args = parts[1:]
-		if len(parts) > 1 {
-			rawArgs = strings.Join(parts[1:], " ")
-		}
+		// Keep the original spacing and line breaks of the arguments; Args
+		// are tokenized once the command (and therefore its flags) is known
+		rawArgs = strings.TrimSpace(strings.TrimPrefix(text, parts[0]))
 	} else {
-		// Handle non-command messages - check for AFK mode
-		if h.IsAfkMode() {
-			adapter := NewHandlerAdapter(h)
-			response := `The person you are trying to reach is not available at the moment, in case of an urgency - Reach out via call.`
-			_ = adapter.SendResponse(msgInfo, response)
-		}
+		// Non-command messages only matter for auto-reply rules and AFK
+		h.applyRules(msgInfo, text)
+		h.handleAfk(msg, msgInfo, text)
 		return
 	}
 
@@ -62,12 +61,15 @@ func (h *WhatsMeowEventHandler) handleMessage(msg *waProto.Message, msgInfo type
 		}
 
 		if cmd == nil {
-			return // Silent fail for unknown commands
+			h.replyUnknownCommand(msg, msgInfo, cmdName)
+			return
 		}
 	}
 
+	role := h.resolveRole(msgInfo)
+	name := cmd.Metadata().Name
+
 	// Create command context
-	adapter := NewHandlerAdapter(h)
 	ctx := &framework.Context{
 		Context:     context.Background(),
 		Message:     msg,
@@ -75,13 +77,40 @@ func (h *WhatsMeowEventHandler) handleMessage(msg *waProto.Message, msgInfo type
 		Command:     cmdName,
 		Args:        args,
 		RawArgs:     rawArgs,
-		Handler:     adapter,
+		Role:        role,
+		Logger:      h.commandLogger(cmdName, msgInfo),
+		Locale:      h.localizer(msgInfo),
+		Handler:     NewHandlerAdapter(h),
+		Jobs:        h.jobs,
 	}
+	started := time.Now()
 
-	// Execute command
-	if err := cmd.Execute(ctx); err != nil {
-		fmt.Printf("Command execution error: %v\n", err)
+	// Banned users are ignored altogether
+	if role == framework.RoleBanned {
+		h.recordCommand(ctx, name, started, fmt.Errorf("%w: sender is banned", framework.ErrPermissionDenied))
+		return
+	}
+
+	// Disabled commands and chats outside the allowlist are ignored silently
+	if !h.CommandAllowed(msgInfo, cmd.Metadata(), role) {
+		h.recordCommand(ctx, name, started, fmt.Errorf("%w: command is disabled in this chat", framework.ErrPermissionDenied))
+		return
+	}
+
+	if args == nil {
+		var err error
+		ctx.Args, ctx.Flags, err = framework.ParseArgs(rawArgs, cmd.Metadata())
+		if err != nil {
+			_ = ctx.Handler.SendResponse(msgInfo, framework.Error(fmt.Sprintf("%s\n%s", framework.ErrorText(ctx.Locale, err),
+				ctx.T("usage.line", "Usage: `%s`", framework.Usage(cmd.Metadata())))))
+			h.recordCommand(ctx, name, started, err)
+			return
+		}
 	}
+
+	// Execute command
+	err := cmd.Execute(ctx)
+	h.recordCommand(ctx, name, started, err)
 }
 
 // InitializeCommands sets up all commands in the registry
@@ -89,7 +118,7 @@ func (h *WhatsMeowEventHandler) InitializeCommands() error {
 	registry := h.commandRegistry
 
 	// Register help command
-	helpCmd := handlers.NewHelpCommand(registry)
+	helpCmd := handlers.NewHelpCommand(registry, h)
 	if err := registry.Register(helpCmd); err != nil {
 		return fmt.Errorf("failed to register help command: %w", err)
 	}
@@ -103,10 +132,23 @@ func (h *WhatsMeowEventHandler) InitializeCommands() error {
 		return fmt.Errorf("failed to register supportedlangs command: %w", err)
 	}
 
-	if err := registry.Register(utility.NewDownloadCommand()); err != nil {
+	h.downloader = utility.NewDownloadCommand(h)
+	if err := registry.Register(h.downloader); err != nil {
 		return fmt.Errorf("failed to register download command: %w", err)
 	}
 
+	if err := registry.Register(utility.NewFormatsCommand()); err != nil {
+		return fmt.Errorf("failed to register formats command: %w", err)
+	}
+
+	if err := registry.Register(utility.NewJobsCommand()); err != nil {
+		return fmt.Errorf("failed to register jobs command: %w", err)
+	}
+
+	if err := registry.Register(utility.NewCancelCommand()); err != nil {
+		return fmt.Errorf("failed to register cancel command: %w", err)
+	}
+
 	if err := registry.Register(utility.NewSedCommand()); err != nil {
 		return fmt.Errorf("failed to register sed command: %w", err)
 	}
@@ -121,21 +163,84 @@ func (h *WhatsMeowEventHandler) InitializeCommands() error {
 		return fmt.Errorf("failed to register noafk command: %w", err)
 	}
 
+	if err := registry.Register(utility.NewUILangCommand(h)); err != nil {
+		return fmt.Errorf("failed to register uilang command: %w", err)
+	}
+
+	if err := registry.Register(utility.NewScheduleCommand(h)); err != nil {
+		return fmt.Errorf("failed to register schedule command: %w", err)
+	}
+
+	if err := registry.Register(utility.NewRemindMeCommand(h)); err != nil {
+		return fmt.Errorf("failed to register remindme command: %w", err)
+	}
+
+	if err := registry.Register(utility.NewTimezoneCommand(h)); err != nil {
+		return fmt.Errorf("failed to register timezone command: %w", err)
+	}
+
 	// Register admin commands
-	if err := registry.Register(admin.NewSetModelCommand()); err != nil {
-		return fmt.Errorf("failed to register setmodel command: %w", err)
+	modelCmd, err := admin.NewModelCommand()
+	if err != nil {
+		return fmt.Errorf("failed to build model command: %w", err)
+	}
+	if err := registry.Register(modelCmd); err != nil {
+		return fmt.Errorf("failed to register model command: %w", err)
+	}
+
+	tempCmd, err := admin.NewTempCommand()
+	if err != nil {
+		return fmt.Errorf("failed to build temp command: %w", err)
+	}
+	if err := registry.Register(tempCmd); err != nil {
+		return fmt.Errorf("failed to register temp command: %w", err)
+	}
+
+	// The flat names from before /model and /temp were groups
+	for _, alias := range admin.NewModelAliases() {
+		if err := registry.Register(alias); err != nil {
+			return fmt.Errorf("failed to register %s command: %w", alias.Metadata().Name, err)
+		}
+	}
+
+	if err := registry.Register(admin.NewGrantCommand(h)); err != nil {
+		return fmt.Errorf("failed to register grant command: %w", err)
 	}
 
-	if err := registry.Register(admin.NewGetModelCommand()); err != nil {
-		return fmt.Errorf("failed to register getmodel command: %w", err)
+	if err := registry.Register(admin.NewRevokeCommand(h)); err != nil {
+		return fmt.Errorf("failed to register revoke command: %w", err)
 	}
 
-	if err := registry.Register(admin.NewSetTempCommand()); err != nil {
-		return fmt.Errorf("failed to register settemp command: %w", err)
+	if err := registry.Register(admin.NewRolesCommand(h)); err != nil {
+		return fmt.Errorf("failed to register roles command: %w", err)
 	}
 
-	if err := registry.Register(admin.NewGetTempCommand()); err != nil {
-		return fmt.Errorf("failed to register gettemp command: %w", err)
+	if err := registry.Register(admin.NewAuditCommand(h)); err != nil {
+		return fmt.Errorf("failed to register audit command: %w", err)
+	}
+
+	if err := registry.Register(admin.NewDisableCommand(registry, h)); err != nil {
+		return fmt.Errorf("failed to register disable command: %w", err)
+	}
+
+	if err := registry.Register(admin.NewEnableCommand(registry, h)); err != nil {
+		return fmt.Errorf("failed to register enable command: %w", err)
+	}
+
+	ruleCmd, err := admin.NewRuleCommand(h)
+	if err != nil {
+		return fmt.Errorf("failed to build rule command: %w", err)
+	}
+	if err := registry.Register(ruleCmd); err != nil {
+		return fmt.Errorf("failed to register rule command: %w", err)
+	}
+
+	chatConfigCmd, err := admin.NewChatConfigCommand(h)
+	if err != nil {
+		return fmt.Errorf("failed to build chatconfig command: %w", err)
+	}
+	if err := registry.Register(chatConfigCmd); err != nil {
+		return fmt.Errorf("failed to register chatconfig command: %w", err)
 	}
 
 	// Register fun commands
@@ -160,14 +265,23 @@ func (h *WhatsMeowEventHandler) InitializeCommands() error {
 		return fmt.Errorf("failed to register translation commands: %w", err)
 	}
 
-	// Apply middleware to commands that need owner permissions based on metadata
-	allCommands := registry.GetAll()
-	for name, cmd := range allCommands {
-		meta := cmd.Metadata()
-		if meta.RequireOwner {
-			wrappedCmd := framework.WithMiddleware(cmd, framework.RequireOwner())
-			// Update the command with middleware
-			registry.UpdateCommand(name, wrappedCmd)
+	// Apply rate limits declared in metadata
+	registry.ApplyMiddleware(func(meta *framework.Metadata) bool {
+		return meta.RateLimit != nil
+	}, framework.RateLimit(h.limiter))
+
+	// Apply middleware to commands (and subcommands) that need a minimum
+	// role based on metadata; applied after rate limits so it runs before them
+	registry.ApplyMiddleware(func(meta *framework.Metadata) bool {
+		return meta.RequiredRole() > framework.RoleEveryone
+	}, framework.RequireRole())
+
+	// Every command recovers from panics, runs with a timeout and reports
+	// failures to the owner. Recover is applied last so that it also covers
+	// the role and rate limit checks; subcommands run inside their group's.
+	for name, cmd := range registry.GetAll() {
+		if err := registry.UpdateCommand(name, framework.WithMiddleware(cmd, framework.Recover(h))); err != nil {
+			return err
 		}
 	}
 
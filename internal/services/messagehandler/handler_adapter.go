@@ -32,6 +32,14 @@ func (a *HandlerAdapter) SendResponse(msgInfo types.MessageInfo, text string) er
 	return nil
 }
 
+func (a *HandlerAdapter) SendResponseWithID(msgInfo types.MessageInfo, text string) (types.MessageID, error) {
+	// Our own command messages are edited in place
+	if msgInfo.IsFromMe {
+		return msgInfo.ID, a.editMessageContent(msgInfo.Chat, msgInfo.ID, text, nil)
+	}
+	return a.sendReplyMessage(msgInfo.Chat, text, msgInfo.ID)
+}
+
 func (a *HandlerAdapter) SendMedia(msgInfo types.MessageInfo, mediaType framework.MediaType, data []byte, caption string) error {
 	ctx := context.Background()
 
@@ -121,6 +129,10 @@ func (a *HandlerAdapter) EditMessageWithOriginal(msgInfo types.MessageInfo, newT
 	return a.WhatsMeowEventHandler.editMessageContent(msgInfo.Chat, msgInfo.ID, newText, originalMsg)
 }
 
+func (a *HandlerAdapter) SendTranslation(msgInfo types.MessageInfo, sourceID types.MessageID, targetLang, text string) error {
+	return a.WhatsMeowEventHandler.sendTranslation(msgInfo, sourceID, targetLang, text)
+}
+
 func (a *HandlerAdapter) GetClient() framework.ClientInterface {
 	return &ClientAdapter{client: a.client}
 }
@@ -0,0 +1,83 @@
+package messagehandler
+
+import (
+	"context"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/i18n"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	settingUILanguage       = "ui_lang"
+	settingSenderUILanguage = "ui_lang_user"
+)
+
+// localizer returns the localizer for the UI language of the sender of
+// msgInfo: their own choice, else the chat's, else UI_LANGUAGE.
+func (h *WhatsMeowEventHandler) localizer(msgInfo types.MessageInfo) *i18n.Localizer {
+	lang, _, err := h.UILanguage(msgInfo)
+	if err != nil {
+		h.logger().Error().Err(err).Str("chat", msgInfo.Chat.String()).Msg("Failed to resolve UI language")
+	}
+	return h.catalog.Localizer(lang)
+}
+
+// UILanguage implements [utility.UILanguageManager]. source is "user",
+// "chat" or "default" depending on where the language was set.
+func (h *WhatsMeowEventHandler) UILanguage(msgInfo types.MessageInfo) (lang, source string, err error) {
+	ctx := context.Background()
+	if h.client.Store.ID != nil {
+		value, ok, err := h.chatSettings.Get(ctx, h.accountID(), auditSender(msgInfo), settingSenderUILanguage)
+		if err != nil {
+			return config.AppConfig.UILanguage, "default", err
+		} else if ok {
+			return value, "user", nil
+		}
+
+		value, ok, err = h.chatSettings.Get(ctx, h.accountID(), msgInfo.Chat.ToNonAD().String(), settingUILanguage)
+		if err != nil {
+			return config.AppConfig.UILanguage, "default", err
+		} else if ok {
+			return value, "chat", nil
+		}
+	}
+	return config.AppConfig.UILanguage, "default", nil
+}
+
+// SetSenderUILanguage implements [utility.UILanguageManager]; an empty lang
+// clears the sender's choice.
+func (h *WhatsMeowEventHandler) SetSenderUILanguage(msgInfo types.MessageInfo, lang string) error {
+	return h.setSetting(auditSender(msgInfo), settingSenderUILanguage, lang)
+}
+
+// SetChatUILanguage implements [utility.UILanguageManager]; an empty lang
+// clears the chat's default.
+func (h *WhatsMeowEventHandler) SetChatUILanguage(chat types.JID, lang string) error {
+	return h.setSetting(chat.ToNonAD().String(), settingUILanguage, lang)
+}
+
+func (h *WhatsMeowEventHandler) setSetting(chat, key, value string) error {
+	ctx := context.Background()
+	if value == "" {
+		return h.chatSettings.Delete(ctx, h.accountID(), chat, key)
+	}
+	return h.chatSettings.Set(ctx, h.accountID(), chat, key, value)
+}
+
+// LoadUIText implements [i18n.Cache].
+func (h *WhatsMeowEventHandler) LoadUIText(lang, key string) (string, string, bool, error) {
+	return h.uiStrings.Get(context.Background(), h.accountID(), lang, key)
+}
+
+// SaveUIText implements [i18n.Cache].
+func (h *WhatsMeowEventHandler) SaveUIText(lang, key, source, text string) error {
+	return h.uiStrings.Save(context.Background(), h.accountID(), lang, key, source, text)
+}
+
+// translateUIText machine translates a reply missing from the built-in
+// catalogs.
+func (h *WhatsMeowEventHandler) translateUIText(_ context.Context, text, lang string) (string, error) {
+	return h.translator.TranslateText(text, utils.GetLangByCode(i18n.DefaultLanguage), utils.GetLangByCode(lang))
+}
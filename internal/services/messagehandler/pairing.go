@@ -0,0 +1,177 @@
+package messagehandler
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/server"
+	"github.com/mdp/qrterminal/v3"
+	"go.mau.fi/whatsmeow"
+)
+
+// pairRetryDelay is how long to wait before generating new codes after the
+// previous batch expired or pairing failed.
+const pairRetryDelay = 5 * time.Second
+
+type pairingState struct {
+	mu      sync.RWMutex
+	active  bool
+	stopped bool
+	cancel  context.CancelFunc
+	status  server.PairingStatus
+}
+
+func (p *pairingState) set(update func(status *server.PairingStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	update(&p.status)
+	p.status.UpdatedAt = time.Now()
+}
+
+// PairingStatus implements [server.PairingSource].
+func (h *WhatsMeowEventHandler) PairingStatus() server.PairingStatus {
+	h.pairing.mu.RLock()
+	defer h.pairing.mu.RUnlock()
+
+	status := h.pairing.status
+	if !h.pairing.active && h.client.Store.ID != nil {
+		status.State = server.PairingPaired
+	}
+	return status
+}
+
+func (h *WhatsMeowEventHandler) isPairing() bool {
+	h.pairing.mu.RLock()
+	defer h.pairing.mu.RUnlock()
+	return h.pairing.active
+}
+
+// startPairing links the device in the background. It keeps producing fresh
+// codes until a phone scans one, so the /pair page never goes stale.
+func (h *WhatsMeowEventHandler) startPairing() {
+	h.pairing.mu.Lock()
+	if h.pairing.active || h.pairing.stopped {
+		h.pairing.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.pairing.active = true
+	h.pairing.cancel = cancel
+	h.pairing.mu.Unlock()
+
+	go func() {
+		defer func() {
+			h.pairing.mu.Lock()
+			h.pairing.active = false
+			h.pairing.mu.Unlock()
+			cancel()
+		}()
+
+		for {
+			paired, err := h.pairOnce(ctx)
+			if paired || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				h.logger().Warn().Err(err).Msg("Pairing attempt failed")
+				h.pairing.set(func(status *server.PairingStatus) {
+					status.State = server.PairingError
+					status.Error = err.Error()
+					status.QRCode = ""
+					status.PairCode = ""
+				})
+			}
+			h.client.Disconnect()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pairRetryDelay):
+			}
+		}
+	}()
+}
+
+// StopPairing stops linking the device for good, e.g. when its account is
+// removed.
+func (h *WhatsMeowEventHandler) StopPairing() {
+	h.pairing.mu.Lock()
+	h.pairing.stopped = true
+	cancel := h.pairing.cancel
+	h.pairing.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	h.client.Disconnect()
+}
+
+// pairOnce runs a single QR channel until it either succeeds, runs out of
+// codes or ctx is cancelled.
+func (h *WhatsMeowEventHandler) pairOnce(ctx context.Context) (bool, error) {
+	qrChan, err := h.client.GetQRChannel(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := h.client.Connect(); err != nil {
+		return false, err
+	}
+
+	phoneRequested := false
+	for evt := range qrChan {
+		switch evt.Event {
+		case whatsmeow.QRChannelEventCode:
+			h.pairing.set(func(status *server.PairingStatus) {
+				status.State = server.PairingWaiting
+				status.QRCode = evt.Code
+				status.Error = ""
+			})
+			qrterminal.GenerateHalfBlock(evt.Code, qrterminal.L, os.Stdout)
+
+			// The phone pairing code can only be requested once the first QR
+			// code has arrived on a fresh connection.
+			if config.AppConfig.PairPhone != "" && !phoneRequested {
+				phoneRequested = true
+				code, err := h.client.PairPhone(ctx, config.AppConfig.PairPhone, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+				if err != nil {
+					h.logger().Error().Err(err).Msg("Failed to request phone pairing code")
+				} else {
+					h.logger().Info().Str("phone", config.AppConfig.PairPhone).Str("code", code).Msg("Phone pairing code")
+					h.pairing.set(func(status *server.PairingStatus) {
+						status.PairCode = code
+					})
+				}
+			}
+		case whatsmeow.QRChannelSuccess.Event:
+			h.logger().Info().Str("event", evt.Event).Msg("Login event")
+			h.pairing.set(func(status *server.PairingStatus) {
+				status.State = server.PairingPaired
+				status.QRCode = ""
+				status.PairCode = ""
+				status.Error = ""
+			})
+			return true, nil
+		default:
+			h.logger().Info().Str("event", evt.Event).Msg("Login event")
+			if evt.Error != nil {
+				return false, evt.Error
+			}
+		}
+	}
+	return false, nil
+}
+
+// handleLoggedOut drops the dead session and goes back to pairing instead of
+// leaving the process running without a usable connection.
+func (h *WhatsMeowEventHandler) handleLoggedOut() {
+	h.client.Disconnect()
+	if h.client.Store.ID != nil {
+		if err := h.client.Store.Delete(context.Background()); err != nil {
+			h.logger().Error().Err(err).Msg("Failed to delete logged out session")
+		}
+	}
+	h.logger().Warn().Msg("Session logged out, waiting for a new pairing")
+	h.startPairing()
+}
@@ -0,0 +1,186 @@
+package messagehandler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// groupAdminTTL bounds how long group admin lists are cached; changes are
+// also picked up earlier through GroupInfo events.
+const groupAdminTTL = 10 * time.Minute
+
+type groupAdminCache struct {
+	mu      sync.Mutex
+	entries map[types.JID]groupAdminEntry
+	// fetches holds the group info requests in flight, shared by everyone
+	// asking for the same group meanwhile
+	fetches map[types.JID]*groupAdminFetch
+}
+
+type groupAdminEntry struct {
+	fetchedAt time.Time
+	// admins holds the user part of every admin's phone number and LID JID
+	admins map[string]bool
+}
+
+type groupAdminFetch struct {
+	done   chan struct{}
+	admins map[string]bool
+	// stale is set when the group changed during the fetch, so its result
+	// isn't cached
+	stale bool
+}
+
+func (c *groupAdminCache) invalidate(group types.JID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, group)
+	if fetch, ok := c.fetches[group]; ok {
+		fetch.stale = true
+	}
+}
+
+// resolveRole determines the sender's role in the chat of msgInfo. The owner
+// is whoever sends from this account; explicit grants come next and group
+// admins are treated as admins within their group.
+func (h *WhatsMeowEventHandler) resolveRole(msgInfo types.MessageInfo) framework.Role {
+	if msgInfo.IsFromMe {
+		return framework.RoleOwner
+	}
+
+	ctx := context.Background()
+	role := framework.RoleEveryone
+	for _, jid := range senderJIDs(msgInfo) {
+		stored, ok, err := h.roles.Get(ctx, h.accountID(), jid.String())
+		if err != nil {
+			h.logger().Error().Err(err).Str("jid", jid.String()).Msg("Failed to load role")
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if framework.Role(stored) == framework.RoleBanned {
+			return framework.RoleBanned
+		}
+		role = max(role, framework.Role(stored))
+	}
+
+	if msgInfo.IsGroup && role < framework.RoleAdmin && h.isGroupAdmin(msgInfo) {
+		role = framework.RoleAdmin
+	}
+	return role
+}
+
+func (h *WhatsMeowEventHandler) isGroupAdmin(msgInfo types.MessageInfo) bool {
+	admins := h.groupAdmins(msgInfo.Chat)
+	for _, jid := range senderJIDs(msgInfo) {
+		if admins[jid.User] {
+			return true
+		}
+	}
+	return false
+}
+
+// groupAdmins returns the admins of group, fetching them at most once at a
+// time per group. The cache isn't locked during the fetch, so other groups
+// aren't held up.
+func (h *WhatsMeowEventHandler) groupAdmins(group types.JID) map[string]bool {
+	cache := &h.adminCache
+	cache.mu.Lock()
+	if entry, ok := cache.entries[group]; ok && time.Since(entry.fetchedAt) < groupAdminTTL {
+		cache.mu.Unlock()
+		return entry.admins
+	}
+	if fetch, ok := cache.fetches[group]; ok {
+		cache.mu.Unlock()
+		<-fetch.done
+		return fetch.admins
+	}
+	fetch := &groupAdminFetch{done: make(chan struct{})}
+	if cache.fetches == nil {
+		cache.fetches = make(map[types.JID]*groupAdminFetch)
+	}
+	cache.fetches[group] = fetch
+	cache.mu.Unlock()
+
+	fetch.admins = h.fetchGroupAdmins(group)
+
+	cache.mu.Lock()
+	delete(cache.fetches, group)
+	if fetch.admins != nil && !fetch.stale {
+		if cache.entries == nil {
+			cache.entries = make(map[types.JID]groupAdminEntry)
+		}
+		cache.entries[group] = groupAdminEntry{fetchedAt: time.Now(), admins: fetch.admins}
+	}
+	cache.mu.Unlock()
+	close(fetch.done)
+	return fetch.admins
+}
+
+// fetchGroupAdmins asks the server for the admins of group, or returns nil
+// if that fails.
+func (h *WhatsMeowEventHandler) fetchGroupAdmins(group types.JID) map[string]bool {
+	info, err := h.client.GetGroupInfo(context.Background(), group)
+	if err != nil {
+		h.logger().Warn().Err(err).Str("group", group.String()).Msg("Failed to fetch group info")
+		return nil
+	}
+
+	admins := make(map[string]bool)
+	for _, participant := range info.Participants {
+		if !participant.IsAdmin && !participant.IsSuperAdmin {
+			continue
+		}
+		for _, jid := range []types.JID{participant.JID, participant.PhoneNumber, participant.LID} {
+			if !jid.IsEmpty() {
+				admins[jid.User] = true
+			}
+		}
+	}
+
+	return admins
+}
+
+// senderJIDs returns the sender's JIDs; in groups that can be both a LID and
+// a phone number JID.
+func senderJIDs(msgInfo types.MessageInfo) []types.JID {
+	jids := []types.JID{msgInfo.Sender.ToNonAD()}
+	if !msgInfo.SenderAlt.IsEmpty() {
+		jids = append(jids, msgInfo.SenderAlt.ToNonAD())
+	}
+	return jids
+}
+
+// SetRole implements admin.RoleManager.
+func (h *WhatsMeowEventHandler) SetRole(jid types.JID, role framework.Role, grantedBy types.JID) error {
+	return h.roles.Set(context.Background(), h.accountID(), storage.RoleAssignment{
+		JID:       jid.ToNonAD().String(),
+		Role:      int(role),
+		GrantedBy: grantedBy.ToNonAD().String(),
+	})
+}
+
+// RemoveRole implements admin.RoleManager.
+func (h *WhatsMeowEventHandler) RemoveRole(jid types.JID) (bool, error) {
+	return h.roles.Delete(context.Background(), h.accountID(), jid.ToNonAD().String())
+}
+
+// Roles implements admin.RoleManager.
+func (h *WhatsMeowEventHandler) Roles() (map[string]framework.Role, error) {
+	assignments, err := h.roles.List(context.Background(), h.accountID())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]framework.Role, len(assignments))
+	for _, a := range assignments {
+		result[a.JID] = framework.Role(a.Role)
+	}
+	return result, nil
+}
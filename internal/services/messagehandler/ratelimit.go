@@ -0,0 +1,16 @@
+package messagehandler
+
+import (
+	"context"
+	"time"
+)
+
+// LoadBucket implements cmdframework.BucketStore.
+func (h *WhatsMeowEventHandler) LoadBucket(key string) (float64, time.Time, bool, error) {
+	return h.rateLimits.Get(context.Background(), h.accountID(), key)
+}
+
+// SaveBucket implements cmdframework.BucketStore.
+func (h *WhatsMeowEventHandler) SaveBucket(key string, tokens float64, updated time.Time) error {
+	return h.rateLimits.Save(context.Background(), h.accountID(), key, tokens, updated)
+}
@@ -0,0 +1,99 @@
+package messagehandler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// storeMessage keeps the text of incoming messages so that reactions can
+// refer to them later.
+func (h *WhatsMeowEventHandler) storeMessage(msg *waProto.Message, msgInfo types.MessageInfo) {
+	text := extractText(msg)
+	if text == "" {
+		return
+	}
+
+	err := h.messages.Save(context.Background(), h.accountID(), storage.Message{
+		Chat:      msgInfo.Chat.String(),
+		ID:        msgInfo.ID,
+		Sender:    msgInfo.Sender.ToNonAD().String(),
+		Text:      text,
+		Timestamp: msgInfo.Timestamp,
+	})
+	if err != nil {
+		h.logger().Error().Err(err).Str("message", msgInfo.ID).Msg("Failed to store message")
+	}
+}
+
+// handleReaction translates the reacted-to message when the reaction is a
+// flag configured in REACTION_FLAGS and the reacting user is allowed to.
+func (h *WhatsMeowEventHandler) handleReaction(reaction *waProto.ReactionMessage, msgInfo types.MessageInfo) {
+	// an empty reaction means a previous reaction was removed
+	emoji := strings.TrimSpace(reaction.GetText())
+	if emoji == "" {
+		return
+	}
+
+	targetLang, ok := config.AppConfig.ReactionFlags[emoji]
+	if !ok {
+		return
+	}
+
+	if !isReactionAllowed(msgInfo) {
+		return
+	}
+
+	ctx := context.Background()
+	targetID := reaction.GetKey().GetID()
+
+	stored, err := h.messages.Get(ctx, h.accountID(), msgInfo.Chat.String(), targetID)
+	if errors.Is(err, storage.ErrMessageNotFound) {
+		h.logger().Debug().Str("message", targetID).Msg("Reaction to unknown message, nothing to translate")
+		return
+	} else if err != nil {
+		h.logger().Error().Err(err).Str("message", targetID).Msg("Failed to load reacted message")
+		return
+	}
+
+	translated, _, err := h.Translate(ctx, stored.Text, "", targetLang)
+	if err != nil {
+		h.logger().Warn().Err(err).Msg("Reaction translation failed")
+		return
+	}
+
+	sender, err := types.ParseJID(stored.Sender)
+	if err != nil {
+		sender = msgInfo.Chat
+	}
+
+	replyID, err := h.sendQuotedReply(msgInfo.Chat, translated, targetID, sender, stored.Text)
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Reply failed")
+		return
+	}
+
+	err = h.translations.Save(ctx, h.accountID(), storage.Translation{
+		Chat:          msgInfo.Chat.String(),
+		SourceID:      targetID,
+		TranslationID: replyID,
+		TargetLang:    targetLang,
+	})
+	if err != nil {
+		h.logger().Error().Err(err).Str("source", targetID).Msg("Failed to remember translation")
+	}
+}
+
+func isReactionAllowed(msgInfo types.MessageInfo) bool {
+	if msgInfo.IsFromMe {
+		return true
+	}
+	return slices.Contains(config.AppConfig.ReactionUsers, msgInfo.Sender.User) ||
+		(!msgInfo.SenderAlt.IsEmpty() && slices.Contains(config.AppConfig.ReactionUsers, msgInfo.SenderAlt.User))
+}
@@ -0,0 +1,95 @@
+package messagehandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/utility"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// scheduleKindReminder pings its creator, replying to the quoted message
+const scheduleKindReminder = "reminder"
+
+// AddReminder implements [utility.ReminderManager].
+func (h *WhatsMeowEventHandler) AddReminder(msgInfo types.MessageInfo, at time.Time, text string, quote utility.QuotedMessage) (int64, error) {
+	return h.schedules.Add(context.Background(), h.accountID(), storage.Schedule{
+		Kind:        scheduleKindReminder,
+		Chat:        msgInfo.Chat.ToNonAD().String(),
+		Creator:     auditSender(msgInfo),
+		Text:        text,
+		Timezone:    timezoneName(at.Location()),
+		QuoteID:     quote.ID,
+		QuoteSender: quote.Sender.ToNonAD().String(),
+		QuoteText:   quote.Text,
+		NextRun:     at,
+	})
+}
+
+// Reminders implements [utility.ReminderManager].
+func (h *WhatsMeowEventHandler) Reminders(msgInfo types.MessageInfo) ([]utility.Reminder, error) {
+	schedules, err := h.schedules.List(context.Background(), h.accountID(), storage.ScheduleQuery{
+		Kind:    scheduleKindReminder,
+		Chat:    msgInfo.Chat.ToNonAD().String(),
+		Creator: auditSender(msgInfo),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]utility.Reminder, len(schedules))
+	for i, sc := range schedules {
+		result[i] = utility.Reminder{ID: sc.ID, Text: sc.Text, At: sc.NextRun}
+	}
+	return result, nil
+}
+
+// CancelReminder implements [utility.ReminderManager].
+func (h *WhatsMeowEventHandler) CancelReminder(msgInfo types.MessageInfo, id int64) (bool, error) {
+	sc, ok, err := h.schedules.Get(context.Background(), h.accountID(), id)
+	if err != nil || !ok {
+		return false, err
+	}
+	if sc.Kind != scheduleKindReminder || sc.Creator != auditSender(msgInfo) || sc.Chat != msgInfo.Chat.ToNonAD().String() {
+		return false, nil
+	}
+	return h.schedules.Delete(context.Background(), h.accountID(), id)
+}
+
+// sendReminder mentions the creator of a reminder in its chat, replying to
+// the quoted message.
+func (h *WhatsMeowEventHandler) sendReminder(ctx context.Context, sc storage.Schedule) error {
+	chat, err := types.ParseJID(sc.Chat)
+	if err != nil {
+		return err
+	}
+	creator, err := types.ParseJID(sc.Creator)
+	if err != nil {
+		return err
+	}
+
+	locale := h.localizer(types.MessageInfo{MessageSource: types.MessageSource{Chat: chat, Sender: creator}})
+	text := fmt.Sprintf("⏰ @%s %s", creator.User, locale.T("reminder.title", "Reminder"))
+	if sc.Text != "" {
+		text += ": " + sc.Text
+	}
+
+	contextInfo := &waProto.ContextInfo{MentionedJID: []string{creator.String()}}
+	if sc.QuoteID != "" {
+		contextInfo.StanzaID = proto.String(sc.QuoteID)
+		contextInfo.Participant = proto.String(sc.QuoteSender)
+		contextInfo.QuotedMessage = &waProto.Message{Conversation: proto.String(sc.QuoteText)}
+	}
+
+	_, err = h.client.SendMessage(ctx, chat, &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text:        proto.String(text),
+			ContextInfo: contextInfo,
+		},
+	})
+	return err
+}
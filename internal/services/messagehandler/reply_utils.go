@@ -9,18 +9,19 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-// sendReplyMessage sends a text message reply, optionally quoting another message
-func (h *WhatsMeowEventHandler) sendReplyMessage(chatJID types.JID, replyText string, quotedMsgID string) error {
+// sendReplyMessage sends a text message reply, optionally quoting another
+// message, and returns the ID of the sent message
+func (h *WhatsMeowEventHandler) sendReplyMessage(chatJID types.JID, replyText string, quotedMsgID string) (types.MessageID, error) {
 	// Regular message (no quoting)
 	if quotedMsgID == "" {
 		msg := &waProto.Message{
 			Conversation: proto.String(replyText),
 		}
-		_, err := h.client.SendMessage(context.Background(), chatJID, msg)
+		resp, err := h.client.SendMessage(context.Background(), chatJID, msg)
 		if err != nil {
-			return fmt.Errorf("failed to send reply: %w", err)
+			return "", fmt.Errorf("failed to send reply: %w", err)
 		}
-		return nil
+		return resp.ID, nil
 	}
 
 	// Quoted message reply
@@ -43,11 +44,32 @@ func (h *WhatsMeowEventHandler) sendReplyMessage(chatJID types.JID, replyText st
 		},
 	}
 
-	_, err := h.client.SendMessage(context.Background(), chatJID, msg)
+	resp, err := h.client.SendMessage(context.Background(), chatJID, msg)
 	if err != nil {
-		return fmt.Errorf("failed to send quoted reply: %w", err)
+		return "", fmt.Errorf("failed to send quoted reply: %w", err)
 	}
-	return nil
+	return resp.ID, nil
+}
+
+// sendQuotedReply replies to a message whose sender and text are known, so
+// the quote renders correctly in groups as well
+func (h *WhatsMeowEventHandler) sendQuotedReply(chatJID types.JID, replyText string, quotedMsgID string, participant types.JID, quotedText string) (types.MessageID, error) {
+	msg := &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text: proto.String(replyText),
+			ContextInfo: &waProto.ContextInfo{
+				StanzaID:      proto.String(quotedMsgID),
+				Participant:   proto.String(participant.ToNonAD().String()),
+				QuotedMessage: &waProto.Message{Conversation: proto.String(quotedText)},
+			},
+		},
+	}
+
+	resp, err := h.client.SendMessage(context.Background(), chatJID, msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send quoted reply: %w", err)
+	}
+	return resp.ID, nil
 }
 
 // editMessageContent edits any type of message content (text or media caption)
@@ -127,22 +149,21 @@ func (h *WhatsMeowEventHandler) editMessageContent(chatJID types.JID, messageID
 		return fmt.Errorf("failed to send %s edit: %w", mediaType, err)
 	}
 
-	fmt.Printf("%s edited successfully. Server timestamp: %s\n", mediaType, resp.Timestamp)
+	h.logger().Debug().Str("media", mediaType).Time("server_timestamp", resp.Timestamp).Msg("Media caption edited")
 	return nil
 }
 
 func (h *WhatsMeowEventHandler) SendResponse(msgInfo types.MessageInfo, response string) {
-	fmt.Printf("Sending response to %s (isFromMe: %v, ID: %s)\n",
-		msgInfo.Chat.String(), msgInfo.IsFromMe, msgInfo.ID)
+	h.logger().Debug().Str("chat", msgInfo.Chat.String()).Bool("from_me", msgInfo.IsFromMe).Str("message", msgInfo.ID).Msg("Sending response")
 
 	if msgInfo.IsFromMe {
 		if err := h.editMessageContent(msgInfo.Chat, msgInfo.ID, response, nil); err != nil {
-			fmt.Println("Edit failed:", err)
+			h.logger().Error().Err(err).Msg("Edit failed")
 		}
 	} else {
 		// Quote the message that initiated the translation command
-		if err := h.sendReplyMessage(msgInfo.Chat, response, msgInfo.ID); err != nil {
-			fmt.Println("Reply failed:", err)
+		if _, err := h.sendReplyMessage(msgInfo.Chat, response, msgInfo.ID); err != nil {
+			h.logger().Error().Err(err).Msg("Reply failed")
 		}
 	}
 }
@@ -0,0 +1,334 @@
+package messagehandler
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/admin"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ruleCache keeps the compiled rules of an account, reloaded after every
+// change, together with when each rule last answered in each chat and the
+// translations of its reply.
+type ruleCache struct {
+	mu           sync.Mutex
+	loaded       bool
+	rules        []compiledRule
+	lastReply    map[ruleChatKey]time.Time
+	translations map[ruleLangKey]string
+}
+
+type compiledRule struct {
+	storage.Rule
+	re *regexp.Regexp
+}
+
+type ruleChatKey struct {
+	rule int64
+	chat types.JID
+}
+
+type ruleLangKey struct {
+	rule int64
+	lang string
+}
+
+// applyRules answers a non-command message with the first rule that matches
+// it, is within its time window and isn't cooling down in the chat. The
+// account's own messages never trigger rules.
+func (h *WhatsMeowEventHandler) applyRules(msgInfo types.MessageInfo, text string) {
+	if msgInfo.IsFromMe || text == "" || h.client.Store.ID == nil {
+		return
+	}
+
+	rules, err := h.loadRules()
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Failed to load rules")
+		return
+	}
+
+	now := time.Now()
+	chat := msgInfo.Chat.ToNonAD()
+	for _, rule := range rules {
+		if !ruleApplies(rule, msgInfo) || !ruleMatches(rule, text) || !ruleActive(rule.Rule, now) {
+			continue
+		}
+		// The role is only looked up once a rule matches, as it may take a
+		// group info request
+		if h.resolveRole(msgInfo) == framework.RoleBanned {
+			return
+		}
+		if !h.claimRuleReply(rule.Rule, chat, now) {
+			continue
+		}
+
+		h.logger().Debug().Int64("rule", rule.ID).Str("chat", chat.String()).Msg("Answering with rule")
+		_ = NewHandlerAdapter(h).SendResponse(msgInfo, h.ruleReply(rule.Rule, text))
+		return
+	}
+}
+
+// loadRules returns the account's rules, loading them on first use.
+func (h *WhatsMeowEventHandler) loadRules() ([]compiledRule, error) {
+	cache := &h.ruleCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.loaded {
+		return cache.rules, nil
+	}
+
+	rules, err := h.rules.List(context.Background(), h.accountID())
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c := compiledRule{Rule: rule}
+		if rule.Regex {
+			if c.re, err = regexp.Compile("(?i)" + rule.Pattern); err != nil {
+				h.logger().Warn().Err(err).Int64("rule", rule.ID).Msg("Skipping rule with invalid pattern")
+				continue
+			}
+		}
+		compiled = append(compiled, c)
+	}
+
+	cache.rules = compiled
+	cache.loaded = true
+	if cache.lastReply == nil {
+		cache.lastReply = make(map[ruleChatKey]time.Time)
+	}
+	cache.translations = make(map[ruleLangKey]string)
+	return compiled, nil
+}
+
+// invalidateRules makes the next message reload the rules.
+func (h *WhatsMeowEventHandler) invalidateRules() {
+	h.ruleCache.mu.Lock()
+	h.ruleCache.loaded = false
+	h.ruleCache.mu.Unlock()
+}
+
+// claimRuleReply reports whether the rule may answer in chat now and, if so,
+// starts its cooldown there.
+func (h *WhatsMeowEventHandler) claimRuleReply(rule storage.Rule, chat types.JID, now time.Time) bool {
+	cache := &h.ruleCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	key := ruleChatKey{rule: rule.ID, chat: chat}
+	if last, ok := cache.lastReply[key]; ok && now.Sub(last) < rule.Cooldown {
+		return false
+	}
+	cache.lastReply[key] = now
+	return true
+}
+
+// ruleCoolingDown reports whether the rule answered in chat within its
+// cooldown, without starting one.
+func (h *WhatsMeowEventHandler) ruleCoolingDown(rule storage.Rule, chat types.JID, now time.Time) bool {
+	h.ruleCache.mu.Lock()
+	defer h.ruleCache.mu.Unlock()
+	last, ok := h.ruleCache.lastReply[ruleChatKey{rule: rule.ID, chat: chat}]
+	return ok && now.Sub(last) < rule.Cooldown
+}
+
+// ruleReply returns the reply of rule, translated to the language text is
+// written in if the rule asks for it. Translations are cached until the
+// rules change; the reply is sent as written if translation fails.
+func (h *WhatsMeowEventHandler) ruleReply(rule storage.Rule, text string) string {
+	if !rule.Translate {
+		return rule.Reply
+	}
+
+	detected, ok := h.detector.DetectLanguage(text)
+	lang := utils.GetCodeByLang(detected)
+	if _, supported := constants.SupportedLanguages[lang]; !ok || !supported {
+		return rule.Reply
+	}
+	if source, ok := h.detector.DetectLanguage(rule.Reply); ok && source == detected {
+		return rule.Reply
+	}
+
+	key := ruleLangKey{rule: rule.ID, lang: lang}
+	h.ruleCache.mu.Lock()
+	cached, isCached := h.ruleCache.translations[key]
+	h.ruleCache.mu.Unlock()
+	if isCached {
+		return cached
+	}
+
+	translated, _, err := h.Translate(context.Background(), rule.Reply, "", lang)
+	if err != nil {
+		h.logger().Warn().Err(err).Int64("rule", rule.ID).Str("lang", lang).Msg("Failed to translate rule reply")
+		return rule.Reply
+	}
+
+	h.ruleCache.mu.Lock()
+	if h.ruleCache.translations != nil {
+		h.ruleCache.translations[key] = translated
+	}
+	h.ruleCache.mu.Unlock()
+	return translated
+}
+
+// ruleApplies reports whether the rule covers the chat of msgInfo.
+func ruleApplies(rule compiledRule, msgInfo types.MessageInfo) bool {
+	switch rule.Chat {
+	case storage.RuleChatAny:
+		return msgInfo.Chat.Server == types.DefaultUserServer || msgInfo.Chat.Server == types.HiddenUserServer ||
+			msgInfo.Chat.Server == types.GroupServer
+	case storage.RuleChatDirect:
+		return msgInfo.Chat.Server == types.DefaultUserServer || msgInfo.Chat.Server == types.HiddenUserServer
+	default:
+		return rule.Chat == msgInfo.Chat.ToNonAD().String()
+	}
+}
+
+func ruleMatches(rule compiledRule, text string) bool {
+	if rule.re != nil {
+		return rule.re.MatchString(text)
+	}
+	return containsWord(text, rule.Pattern)
+}
+
+// containsWord reports whether text contains keyword as whole words,
+// ignoring case.
+func containsWord(text, keyword string) bool {
+	text, keyword = strings.ToLower(text), strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return false
+	}
+
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) }
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], keyword)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(keyword)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWord(before)) && (end == len(text) || !isWord(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+}
+
+// ruleActive reports whether now is within the rule's time window.
+func ruleActive(rule storage.Rule, now time.Time) bool {
+	if rule.WindowFrom == "" || rule.WindowTo == "" {
+		return true
+	}
+	from, okFrom := clockMinutes(rule.WindowFrom)
+	to, okTo := clockMinutes(rule.WindowTo)
+	if !okFrom || !okTo {
+		return true
+	}
+
+	local := now.In(scheduleLocation(rule.Timezone))
+	minute := local.Hour()*60 + local.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	// The window spans midnight, e.g. 22:00-07:00
+	return minute >= from || minute < to
+}
+
+func clockMinutes(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// AddRule implements [admin.RuleManager]. The time window is in the
+// creator's timezone.
+func (h *WhatsMeowEventHandler) AddRule(msgInfo types.MessageInfo, rule admin.AutoReplyRule) (int64, error) {
+	defer h.invalidateRules()
+	return h.rules.Add(context.Background(), h.accountID(), storage.Rule{
+		Chat:       rule.Chat,
+		Pattern:    rule.Pattern,
+		Regex:      rule.Regex,
+		Reply:      rule.Reply,
+		Translate:  rule.Translate,
+		Cooldown:   rule.Cooldown,
+		WindowFrom: rule.From,
+		WindowTo:   rule.To,
+		Timezone:   timezoneName(h.UserLocation(msgInfo)),
+		Creator:    auditSender(msgInfo),
+	})
+}
+
+// Rules implements [admin.RuleManager].
+func (h *WhatsMeowEventHandler) Rules() ([]admin.AutoReplyRule, error) {
+	rules, err := h.rules.List(context.Background(), h.accountID())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]admin.AutoReplyRule, len(rules))
+	for i, rule := range rules {
+		result[i] = toAutoReplyRule(rule)
+	}
+	return result, nil
+}
+
+// RemoveRule implements [admin.RuleManager].
+func (h *WhatsMeowEventHandler) RemoveRule(id int64) (bool, error) {
+	defer h.invalidateRules()
+	return h.rules.Delete(context.Background(), h.accountID(), id)
+}
+
+// TestRules implements [admin.RuleManager]. Unlike incoming messages, the
+// test message may come from the account itself.
+func (h *WhatsMeowEventHandler) TestRules(msgInfo types.MessageInfo, text string) ([]admin.RuleMatch, error) {
+	rules, err := h.loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var matches []admin.RuleMatch
+	for _, rule := range rules {
+		if !ruleApplies(rule, msgInfo) || !ruleMatches(rule, text) {
+			continue
+		}
+		matches = append(matches, admin.RuleMatch{
+			Rule:        toAutoReplyRule(rule.Rule),
+			Active:      ruleActive(rule.Rule, now),
+			CoolingDown: h.ruleCoolingDown(rule.Rule, msgInfo.Chat.ToNonAD(), now),
+			Reply:       h.ruleReply(rule.Rule, text),
+		})
+	}
+	return matches, nil
+}
+
+func toAutoReplyRule(rule storage.Rule) admin.AutoReplyRule {
+	return admin.AutoReplyRule{
+		ID:        rule.ID,
+		Chat:      rule.Chat,
+		Pattern:   rule.Pattern,
+		Regex:     rule.Regex,
+		Reply:     rule.Reply,
+		Translate: rule.Translate,
+		Cooldown:  rule.Cooldown,
+		From:      rule.WindowFrom,
+		To:        rule.WindowTo,
+	}
+}
@@ -0,0 +1,199 @@
+package messagehandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/handlers/utility"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// scheduleKindMessage sends the text to the chat as is, or translated
+	scheduleKindMessage = "message"
+
+	schedulerInterval = 15 * time.Second
+	// maxScheduleDelay drops schedules that keep failing for this long past
+	// their time
+	maxScheduleDelay = 24 * time.Hour
+)
+
+// runScheduler delivers due schedules until ctx is cancelled. Schedules that
+// came due while the bot was offline are delivered once it is back.
+func (h *WhatsMeowEventHandler) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if h.client.Store.ID == nil || !h.client.IsLoggedIn() {
+			continue
+		}
+		due, err := h.schedules.Due(ctx, h.accountID(), time.Now())
+		if err != nil {
+			h.logger().Error().Err(err).Msg("Failed to load due schedules")
+			continue
+		}
+		for _, sc := range due {
+			h.fireSchedule(ctx, sc)
+		}
+	}
+}
+
+// StopScheduler stops delivering schedules, e.g. on shutdown.
+func (h *WhatsMeowEventHandler) StopScheduler() {
+	if h.stopScheduler != nil {
+		h.stopScheduler()
+	}
+}
+
+func (h *WhatsMeowEventHandler) fireSchedule(ctx context.Context, sc storage.Schedule) {
+	logger := h.logger().With().Int64("schedule", sc.ID).Str("kind", sc.Kind).Str("chat", sc.Chat).Logger()
+
+	var err error
+	switch sc.Kind {
+	case scheduleKindMessage:
+		err = h.sendScheduledMessage(ctx, sc)
+	case scheduleKindReminder:
+		err = h.sendReminder(ctx, sc)
+	default:
+		logger.Warn().Msg("Dropping schedule of unknown kind")
+	}
+
+	now := time.Now()
+	if err != nil {
+		if now.Sub(sc.NextRun) < maxScheduleDelay {
+			logger.Warn().Err(err).Msg("Failed to deliver schedule, retrying")
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to deliver schedule, giving up")
+	}
+
+	if next, ok := nextRun(sc, now); ok {
+		err = h.schedules.Reschedule(ctx, h.accountID(), sc.ID, next)
+	} else {
+		_, err = h.schedules.Delete(ctx, h.accountID(), sc.ID)
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update schedule")
+	}
+}
+
+func (h *WhatsMeowEventHandler) sendScheduledMessage(ctx context.Context, sc storage.Schedule) error {
+	chat, err := types.ParseJID(sc.Chat)
+	if err != nil {
+		return err
+	}
+
+	text := sc.Text
+	if sc.Lang != "" {
+		// Translated at send time so the current model is used; an untranslated
+		// message is better than none
+		if translated, _, err := h.Translate(ctx, text, "", sc.Lang); err != nil {
+			h.logger().Warn().Err(err).Int64("schedule", sc.ID).Msg("Failed to translate scheduled message")
+		} else {
+			text = translated
+		}
+	}
+
+	_, err = h.SendText(ctx, chat, text)
+	return err
+}
+
+// nextRun returns the first run of a recurring schedule after now, keeping
+// its wall clock time in the schedule's timezone.
+func nextRun(sc storage.Schedule, now time.Time) (time.Time, bool) {
+	days := 0
+	switch sc.Repeat {
+	case storage.RepeatDaily:
+		days = 1
+	case storage.RepeatWeekly:
+		days = 7
+	default:
+		return time.Time{}, false
+	}
+
+	next := sc.NextRun.In(scheduleLocation(sc.Timezone))
+	for !next.After(now) {
+		next = next.AddDate(0, 0, days)
+	}
+	return next, true
+}
+
+// scheduleLocation loads a schedule's timezone, falling back to the
+// server's.
+func scheduleLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// ScheduleMessage implements [utility.MessageScheduler].
+func (h *WhatsMeowEventHandler) ScheduleMessage(msgInfo types.MessageInfo, at time.Time, text, lang, repeat string) (int64, error) {
+	return h.schedules.Add(context.Background(), h.accountID(), storage.Schedule{
+		Kind:     scheduleKindMessage,
+		Chat:     msgInfo.Chat.ToNonAD().String(),
+		Creator:  auditSender(msgInfo),
+		Text:     text,
+		Lang:     lang,
+		Repeat:   repeat,
+		Timezone: timezoneName(at.Location()),
+		NextRun:  at,
+	})
+}
+
+// ScheduledMessages implements [utility.MessageScheduler].
+func (h *WhatsMeowEventHandler) ScheduledMessages(chat types.JID) ([]utility.ScheduledMessage, error) {
+	query := storage.ScheduleQuery{Kind: scheduleKindMessage}
+	if !chat.IsEmpty() {
+		query.Chat = chat.ToNonAD().String()
+	}
+	schedules, err := h.schedules.List(context.Background(), h.accountID(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]utility.ScheduledMessage, len(schedules))
+	for i, sc := range schedules {
+		result[i] = toScheduledMessage(sc)
+	}
+	return result, nil
+}
+
+// ScheduledMessage implements [utility.MessageScheduler].
+func (h *WhatsMeowEventHandler) ScheduledMessage(id int64) (utility.ScheduledMessage, bool, error) {
+	sc, ok, err := h.schedules.Get(context.Background(), h.accountID(), id)
+	if err != nil || !ok || sc.Kind != scheduleKindMessage {
+		return utility.ScheduledMessage{}, false, err
+	}
+	return toScheduledMessage(sc), true, nil
+}
+
+// CancelSchedule implements [utility.MessageScheduler].
+func (h *WhatsMeowEventHandler) CancelSchedule(id int64) error {
+	_, err := h.schedules.Delete(context.Background(), h.accountID(), id)
+	return err
+}
+
+func toScheduledMessage(sc storage.Schedule) utility.ScheduledMessage {
+	chat, _ := types.ParseJID(sc.Chat)
+	return utility.ScheduledMessage{
+		ID:      sc.ID,
+		Chat:    chat,
+		Creator: sc.Creator,
+		Text:    sc.Text,
+		Lang:    sc.Lang,
+		Repeat:  sc.Repeat,
+		NextRun: sc.NextRun,
+	}
+}
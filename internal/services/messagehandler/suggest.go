@@ -0,0 +1,108 @@
+package messagehandler
+
+import (
+	"context"
+	"unicode"
+
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	settingSuggestions = "suggestions"
+	maxSuggestions     = 3
+)
+
+// replyUnknownCommand answers an unknown command with the closest commands
+// the sender may run. It stays quiet in groups unless the bot is mentioned or
+// replied to, and for the owner's own messages outside their self chat, which
+// would otherwise be edited.
+func (h *WhatsMeowEventHandler) replyUnknownCommand(msg *waProto.Message, msgInfo types.MessageInfo, name string) {
+	if h.client.Store.ID == nil || !looksLikeCommand(name) {
+		return
+	}
+	if msgInfo.IsFromMe && msgInfo.Chat.ToNonAD() != h.client.Store.ID.ToNonAD() {
+		return
+	}
+	if msgInfo.IsGroup && !h.isAddressed(msg) {
+		return
+	}
+
+	role := h.resolveRole(msgInfo)
+	if role == framework.RoleBanned || !h.chatAllowed(msgInfo, role) {
+		return
+	}
+	if enabled, err := h.SuggestionsEnabled(msgInfo.Chat); err != nil || !enabled {
+		return
+	}
+
+	suggestions := h.commandRegistry.Suggest(name, maxSuggestions, func(cmd framework.Command) bool {
+		meta := cmd.Metadata()
+		return role >= meta.RequiredRole() && h.CommandAllowed(msgInfo, meta, role)
+	})
+	_ = NewHandlerAdapter(h).SendResponse(msgInfo, framework.UnknownCommand(h.localizer(msgInfo), name, suggestions))
+}
+
+// looksLikeCommand filters out text that merely starts with a slash, such as
+// paths, short emoticons or "/ ".
+func looksLikeCommand(name string) bool {
+	if len(name) < 3 || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAddressed reports whether msg mentions the bot account or replies to one
+// of its messages.
+func (h *WhatsMeowEventHandler) isAddressed(msg *waProto.Message) bool {
+	contextInfo := msg.GetExtendedTextMessage().GetContextInfo()
+	if contextInfo == nil {
+		return false
+	}
+
+	own := []types.JID{h.client.Store.ID.ToNonAD()}
+	if !h.client.Store.LID.IsEmpty() {
+		own = append(own, h.client.Store.LID.ToNonAD())
+	}
+	isOwn := func(value string) bool {
+		jid, err := types.ParseJID(value)
+		if err != nil {
+			return false
+		}
+		for _, o := range own {
+			if jid.User == o.User && jid.Server == o.Server {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, mentioned := range contextInfo.GetMentionedJID() {
+		if isOwn(mentioned) {
+			return true
+		}
+	}
+	return contextInfo.GetStanzaID() != "" && isOwn(contextInfo.GetParticipant())
+}
+
+// SetSuggestions implements admin.ChatConfigManager.
+func (h *WhatsMeowEventHandler) SetSuggestions(chat types.JID, enabled bool) error {
+	ctx := context.Background()
+	if enabled {
+		return h.chatSettings.Delete(ctx, h.accountID(), chat.ToNonAD().String(), settingSuggestions)
+	}
+	return h.chatSettings.Set(ctx, h.accountID(), chat.ToNonAD().String(), settingSuggestions, "off")
+}
+
+// SuggestionsEnabled implements admin.ChatConfigManager. Suggestions are on
+// unless turned off for the chat.
+func (h *WhatsMeowEventHandler) SuggestionsEnabled(chat types.JID) (bool, error) {
+	value, ok, err := h.chatSettings.Get(context.Background(), h.accountID(), chat.ToNonAD().String(), settingSuggestions)
+	return !ok || value != "off", err
+}
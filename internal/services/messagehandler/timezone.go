@@ -0,0 +1,44 @@
+package messagehandler
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+const settingTimezone = "timezone"
+
+// UserTimezone implements [utility.TimezoneManager]. It is empty for senders
+// that use the server's timezone.
+func (h *WhatsMeowEventHandler) UserTimezone(msgInfo types.MessageInfo) (string, error) {
+	if h.client.Store.ID == nil {
+		return "", nil
+	}
+	name, _, err := h.chatSettings.Get(context.Background(), h.accountID(), auditSender(msgInfo), settingTimezone)
+	return name, err
+}
+
+// SetUserTimezone implements [utility.TimezoneManager]; an empty name
+// clears the sender's timezone.
+func (h *WhatsMeowEventHandler) SetUserTimezone(msgInfo types.MessageInfo, name string) error {
+	return h.setSetting(auditSender(msgInfo), settingTimezone, name)
+}
+
+// UserLocation implements [utility.TimezoneResolver].
+func (h *WhatsMeowEventHandler) UserLocation(msgInfo types.MessageInfo) *time.Location {
+	name, err := h.UserTimezone(msgInfo)
+	if err != nil {
+		h.logger().Error().Err(err).Msg("Failed to load timezone")
+	}
+	return scheduleLocation(name)
+}
+
+// timezoneName is the name a schedule stores for loc; the server's timezone
+// is stored as empty so it follows TZ changes.
+func timezoneName(loc *time.Location) string {
+	if loc == time.Local {
+		return ""
+	}
+	return loc.String()
+}
@@ -1,7 +1,6 @@
 package messagehandler
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
@@ -24,33 +23,33 @@ func (h *WhatsMeowEventHandler) handleMediaCaptionTranslation(msg *waProto.Messa
 
 	parts := strings.SplitN(caption, " ", 2)
 	if len(parts) < 2 {
-		fmt.Println("Caption starts with / but has no space - not a translation command")
+		h.logger().Debug().Msg("Caption starts with / but has no space, not a translation command")
 		return false
 	}
 
 	langCode := strings.TrimPrefix(parts[0], "/")
 	targetLang := utils.GetLangByCode(langCode)
 	if targetLang == lingua.Unknown {
-		fmt.Println("Not a valid language code in media caption - ignoring")
+		h.logger().Debug().Msg("Not a valid language code in media caption, ignoring")
 		return false
 	}
 
 	textToTranslate := parts[1]
 	detectedLang, ok := h.detector.DetectLanguage(textToTranslate)
 	if !ok {
-		fmt.Printf("language detection failed:\n")
+		h.logger().Debug().Msg("Language detection failed")
 		return false
 	}
 
 	translated, err := h.translator.TranslateText(textToTranslate, detectedLang, targetLang)
 	if err != nil {
-		fmt.Printf("caption translation failed: %v\n", err)
+		h.logger().Warn().Err(err).Msg("Caption translation failed")
 		return false
 	}
 
 	if msgInfo.IsFromMe {
 		if err := h.editMessageContent(msgInfo.Chat, msgInfo.ID, translated, msg); err != nil {
-			fmt.Printf("caption edit failed: %v\n", err)
+			h.logger().Error().Err(err).Msg("Caption edit failed")
 			h.SendResponse(msgInfo, translated)
 		}
 	} else {
@@ -67,38 +66,38 @@ func (h *WhatsMeowEventHandler) handleQuotedMessageTranslation(msg *waProto.Mess
 
 	parts := strings.SplitN(text, " ", 2)
 	if len(parts) > 1 {
-		fmt.Printf("Inline translation command \"%s\": %s\n", parts[0], text)
+		h.logger().Debug().Str("command", parts[0]).Msg("Inline translation command")
 		return false
 	}
 
 	langCode := strings.TrimPrefix(text, "/")
 	targetLang := utils.GetLangByCode(langCode)
 	if targetLang == lingua.Unknown {
-		fmt.Printf("Not a valid language code \"%s\" in quoted message - ignoring\n", langCode)
+		h.logger().Debug().Str("code", langCode).Msg("Not a valid language code in quoted message, ignoring")
 		return false
 	}
 
 	quotedMsg, msgType, err := getQuotedMessageAndType(msg)
 	if err != nil {
-		fmt.Println("Quoted message not found or invalid:", err)
+		h.logger().Debug().Err(err).Msg("Quoted message not found or invalid")
 		return true
 	}
 
 	quotedText := extractText(quotedMsg)
 	if quotedText == "" {
-		fmt.Println("Quoted message has no translatable text.")
+		h.logger().Debug().Msg("Quoted message has no translatable text")
 		return true
 	}
 
 	detectedLang, ok := h.detector.DetectLanguage(quotedText)
 	if !ok {
-		fmt.Println("Could not detect source language.")
+		h.logger().Debug().Msg("Could not detect source language")
 		return false
 	}
 
 	translated, err := h.translator.TranslateText(quotedText, detectedLang, targetLang)
 	if err != nil {
-		fmt.Println("Translation error:", err)
+		h.logger().Warn().Err(err).Msg("Translation failed")
 		return false
 	}
 
@@ -107,20 +106,20 @@ func (h *WhatsMeowEventHandler) handleQuotedMessageTranslation(msg *waProto.Mess
 	isMedia := isMediaMessage(msg)
 
 	if msgInfo.IsFromMe && isMedia && quotedMsgID != "" {
-		fmt.Printf("Attempting to edit %s caption\n", msgType)
+		h.logger().Debug().Stringer("media", msgType).Msg("Attempting to edit caption")
 		if err := h.editMessageContent(msgInfo.Chat, quotedMsgID, translated, quotedMsg); err != nil {
-			fmt.Printf("Quoted %s caption edit failed: %v\n", msgType, err)
+			h.logger().Warn().Err(err).Stringer("media", msgType).Msg("Quoted caption edit failed")
 			if err := h.editMessageContent(msgInfo.Chat, msgInfo.ID, translated, nil); err != nil {
-				fmt.Println("Edit fallback failed:", err)
+				h.logger().Error().Err(err).Msg("Edit fallback failed")
 			}
 		}
 	} else if msgInfo.IsFromMe {
 		if err := h.editMessageContent(msgInfo.Chat, msgInfo.ID, translated, nil); err != nil {
-			fmt.Println("Edit failed:", err)
+			h.logger().Error().Err(err).Msg("Edit failed")
 		}
 	} else {
-		if err := h.sendReplyMessage(msgInfo.Chat, translated, msgInfo.ID); err != nil {
-			fmt.Println("Reply failed:", err)
+		if _, err := h.sendReplyMessage(msgInfo.Chat, translated, msgInfo.ID); err != nil {
+			h.logger().Error().Err(err).Msg("Reply failed")
 		}
 	}
 
@@ -134,14 +133,14 @@ func (h *WhatsMeowEventHandler) handleInlineTranslation(text string, msgInfo typ
 
 	parts := strings.SplitN(text, " ", 2)
 	if len(parts) < 2 {
-		fmt.Println("Message starts with / but has no space - not a translation command")
+		h.logger().Debug().Msg("Message starts with / but has no space, not a translation command")
 		return false
 	}
 
 	langCode := strings.TrimPrefix(parts[0], "/")
 	targetLang := utils.GetLangByCode(langCode)
 	if targetLang == lingua.Unknown {
-		fmt.Println("Not a valid language code - ignoring")
+		h.logger().Debug().Msg("Not a valid language code, ignoring")
 		return false
 	}
 
@@ -149,13 +148,13 @@ func (h *WhatsMeowEventHandler) handleInlineTranslation(text string, msgInfo typ
 
 	detectedLang, ok := h.detector.DetectLanguage(textToTranslate)
 	if !ok {
-		fmt.Println("Could not detect source language.")
+		h.logger().Debug().Msg("Could not detect source language")
 		return false
 	}
 
 	translated, err := h.translator.TranslateText(textToTranslate, detectedLang, targetLang)
 	if err != nil {
-		fmt.Println("Translation failed:", err)
+		h.logger().Warn().Err(err).Msg("Translation failed")
 		return false
 	}
 
@@ -2,13 +2,14 @@ package ollama
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"time"
 
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
 	lingua "github.com/pemistahl/lingua-go"
 )
@@ -50,9 +51,28 @@ func (o *OllamaTranslator) SetTemperature(temp float64) error {
 	return nil
 }
 
+// Ping implements [TranslateService].
+func (o *OllamaTranslator) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseUrl+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // TranslateText implements [TranslateService].
 func (o *OllamaTranslator) TranslateText(text string, sourceLang lingua.Language, targetLang lingua.Language) (string, error) {
-	log.Printf("Recieved translation request for: %s to %s with text: %s", sourceLang.IsoCode639_1(), targetLang.IsoCode639_1(), text)
+	logging.Log.Debug().Stringer("from", sourceLang.IsoCode639_1()).Stringer("to", targetLang.IsoCode639_1()).Int("length", len(text)).Msg("Received translation request")
 	req := OllamaTranslateRequestSchema{
 		Model: o.Model,
 		Messages: []struct {
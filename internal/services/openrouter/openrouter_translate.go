@@ -2,6 +2,7 @@ package openrouter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -52,6 +53,26 @@ func (o *OpenrouterTranslator) SetTemperature(temp float64) error {
 	return nil
 }
 
+// Ping implements [services.TranslateService].
+func (o *OpenrouterTranslator) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseUrl+"/api/v1/key", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", "Bearer "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openrouter returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // TranslateText implements [services.TranslateService].
 func (o *OpenrouterTranslator) TranslateText(text string, sourceLang lingua.Language, targetLang lingua.Language) (string, error) {
 	url := o.BaseUrl + "/api/v1/chat/completions"
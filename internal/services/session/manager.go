@@ -0,0 +1,244 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/server"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/dispatcher"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/messagehandler"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+)
+
+const (
+	// maxPendingAccounts bounds the accounts waiting to be paired at once.
+	maxPendingAccounts = 3
+
+	// jobStopTimeout bounds how long DisconnectAll waits for cancelled jobs
+	// to mark their status messages as interrupted.
+	jobStopTimeout = 5 * time.Second
+)
+
+// Options holds the services shared by, or created for, every session.
+// Translators and image generators are created per session so that runtime
+// settings such as /model set only affect the account they were issued on.
+type Options struct {
+	DB                *storage.DB
+	Detector          services.LangDetectService
+	NewTranslator     func() services.TranslateService
+	NewImageGenerator func() services.ImageGenerator
+	// Dispatcher runs message handling for all sessions
+	Dispatcher *dispatcher.Dispatcher
+}
+
+// Session is a single WhatsApp account driven by this process.
+type Session struct {
+	ID      string
+	Client  *whatsmeow.Client
+	Handler *messagehandler.WhatsMeowEventHandler
+}
+
+// Manager owns one client and event handler per device stored in the
+// sqlstore container.
+type Manager struct {
+	container *sqlstore.Container
+	opts      Options
+
+	// addMu serializes AddAccount so the pending limit holds
+	addMu sync.Mutex
+
+	mu        sync.RWMutex
+	sessions  map[string]*Session
+	order     []string
+	pendingID int
+}
+
+func NewManager(container *sqlstore.Container, opts Options) *Manager {
+	return &Manager{
+		container: container,
+		opts:      opts,
+		sessions:  make(map[string]*Session),
+	}
+}
+
+// LoadAll starts a session for every stored device. With no stored devices a
+// fresh one is created so the first account can be paired.
+func (m *Manager) LoadAll(ctx context.Context) error {
+	devices, err := m.container.GetAllDevices(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load devices: %w", err)
+	}
+
+	for _, device := range devices {
+		if _, err := m.start(device.ID.User, device); err != nil {
+			return err
+		}
+	}
+
+	if len(devices) == 0 {
+		if _, err := m.AddAccount(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAccount creates a new device and starts pairing it. At most
+// maxPendingAccounts accounts may be waiting to be paired.
+func (m *Manager) AddAccount() (server.Account, error) {
+	m.addMu.Lock()
+	defer m.addMu.Unlock()
+
+	pending := 0
+	for _, sess := range m.Sessions() {
+		if sess.Handler.PairingStatus().State != server.PairingPaired {
+			pending++
+		}
+	}
+	if pending >= maxPendingAccounts {
+		return server.Account{}, server.ErrTooManyPending
+	}
+
+	m.mu.Lock()
+	m.pendingID++
+	id := fmt.Sprintf("new-%d", m.pendingID)
+	m.mu.Unlock()
+
+	sess, err := m.start(id, m.container.NewDevice())
+	if err != nil {
+		return server.Account{}, err
+	}
+	return toAccount(sess), nil
+}
+
+func (m *Manager) start(id string, device *store.Device) (*Session, error) {
+	client := whatsmeow.NewClient(device, nil)
+
+	handler, err := messagehandler.NewWhatsMeowEventHandler(client, m.opts.Detector, m.opts.NewTranslator(), m.opts.NewImageGenerator(), m.opts.DB, m.opts.Dispatcher)
+	if err != nil {
+		return nil, fmt.Errorf("error while setting up the event handler for %s: %w", id, err)
+	}
+
+	if err := handler.Start(); err != nil {
+		return nil, fmt.Errorf("error while connecting %s: %w", id, err)
+	}
+
+	sess := &Session{ID: id, Client: client, Handler: handler}
+
+	m.mu.Lock()
+	m.sessions[id] = sess
+	m.order = append(m.order, id)
+	m.mu.Unlock()
+
+	logging.Log.Info().Str("account", id).Msg("Session started")
+	return sess, nil
+}
+
+// RemoveAccount implements [server.AccountProvider]. Linked accounts can't
+// be removed; they go back to pairing once unlinked on the phone.
+func (m *Manager) RemoveAccount(id string) error {
+	sess, ok := m.Get(id)
+	if !ok {
+		return server.ErrAccountNotFound
+	}
+	if sess.Client.Store.ID != nil {
+		return server.ErrAccountLinked
+	}
+
+	m.mu.Lock()
+	delete(m.sessions, sess.ID)
+	m.order = slices.DeleteFunc(m.order, func(other string) bool { return other == sess.ID })
+	m.mu.Unlock()
+
+	sess.Handler.StopScheduler()
+	sess.Handler.StopPairing()
+	sess.Handler.CancelJobs()
+	logging.Log.Info().Str("account", sess.ID).Msg("Session removed")
+	return nil
+}
+
+// Sessions returns all sessions in the order they were started.
+func (m *Manager) Sessions() []*Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*Session, 0, len(m.order))
+	for _, id := range m.order {
+		result = append(result, m.sessions[id])
+	}
+	return result
+}
+
+// Get looks a session up by ID or by the phone number of its account.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if sess, ok := m.sessions[id]; ok {
+		return sess, true
+	}
+
+	user := strings.TrimPrefix(strings.SplitN(id, "@", 2)[0], "+")
+	for _, sess := range m.sessions {
+		if sess.Client.Store.ID != nil && sess.Client.Store.ID.User == user {
+			return sess, true
+		}
+	}
+	return nil, false
+}
+
+// DisconnectAll stops the schedulers, cancels running jobs and closes every
+// client connection. The clients stay connected for up to jobStopTimeout so
+// the cancelled jobs can still edit their status messages.
+func (m *Manager) DisconnectAll() {
+	sessions := m.Sessions()
+	for _, sess := range sessions {
+		sess.Handler.StopScheduler()
+		sess.Handler.CancelJobs()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobStopTimeout)
+	defer cancel()
+	for _, sess := range sessions {
+		if err := sess.Handler.WaitJobs(ctx); err != nil {
+			logging.Log.Warn().Err(err).Str("account", sess.ID).Msg("Disconnecting with jobs still running")
+		}
+		sess.Client.Disconnect()
+	}
+}
+
+// Accounts implements [server.AccountProvider].
+func (m *Manager) Accounts() []server.Account {
+	sessions := m.Sessions()
+	accounts := make([]server.Account, 0, len(sessions))
+	for _, sess := range sessions {
+		accounts = append(accounts, toAccount(sess))
+	}
+	return accounts
+}
+
+// Account implements [server.AccountProvider].
+func (m *Manager) Account(id string) (server.Account, bool) {
+	sess, ok := m.Get(id)
+	if !ok {
+		return server.Account{}, false
+	}
+	return toAccount(sess), true
+}
+
+func toAccount(sess *Session) server.Account {
+	account := server.Account{ID: sess.ID, Handler: sess.Handler}
+	if sess.Client.Store.ID != nil {
+		account.JID = sess.Client.Store.ID.ToNonAD()
+	}
+	return account
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/pemistahl/lingua-go"
 )
 
@@ -10,4 +12,6 @@ type TranslateService interface {
 	GetModel() string
 	SetTemperature(temp float64) error
 	GetTemperature() float64
+	// Ping checks that the backend is reachable and the credentials are accepted.
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const auditSchema = `
+CREATE TABLE IF NOT EXISTS audit_log (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	account     TEXT    NOT NULL,
+	timestamp   INTEGER NOT NULL,
+	chat        TEXT    NOT NULL,
+	sender      TEXT    NOT NULL,
+	role        TEXT    NOT NULL,
+	command     TEXT    NOT NULL,
+	args        TEXT    NOT NULL DEFAULT '',
+	outcome     TEXT    NOT NULL,
+	error       TEXT    NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS audit_log_account_timestamp ON audit_log (account, timestamp);
+`
+
+// AuditEntry records one command invocation.
+type AuditEntry struct {
+	ID        int64
+	Timestamp time.Time
+	Chat      string
+	Sender    string
+	Role      string
+	Command   string
+	Args      string
+	Outcome   string
+	Error     string
+	Duration  time.Duration
+}
+
+// AuditQuery filters audit entries; empty fields match everything.
+type AuditQuery struct {
+	Chat    string
+	Sender  string
+	Command string
+	Outcome string
+	Limit   int
+}
+
+type AuditStore struct {
+	db *DB
+}
+
+func (d *DB) Audit() *AuditStore {
+	return &AuditStore{db: d}
+}
+
+func (s *AuditStore) Record(ctx context.Context, account string, e AuditEntry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO audit_log (account, timestamp, chat, sender, role, command, args, outcome, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account, e.Timestamp.Unix(), e.Chat, e.Sender, e.Role, e.Command, e.Args, e.Outcome, e.Error,
+		e.Duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns the newest entries matching q first.
+func (s *AuditStore) Query(ctx context.Context, account string, q AuditQuery) ([]AuditEntry, error) {
+	where := []string{"account = ?"}
+	args := []any{account}
+	for column, value := range map[string]string{
+		"chat":    q.Chat,
+		"sender":  q.Sender,
+		"command": q.Command,
+		"outcome": q.Outcome,
+	} {
+		if value != "" {
+			where = append(where, column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT id, timestamp, chat, sender, role, command, args, outcome, error, duration_ms
+		FROM audit_log WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var result []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var timestamp, durationMs int64
+		if err := rows.Scan(&e.ID, &timestamp, &e.Chat, &e.Sender, &e.Role, &e.Command, &e.Args,
+			&e.Outcome, &e.Error, &durationMs); err != nil {
+			return nil, fmt.Errorf("failed to read audit entry: %w", err)
+		}
+		e.Timestamp = time.Unix(timestamp, 0)
+		e.Duration = time.Duration(durationMs) * time.Millisecond
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// Prune drops entries older than maxAge for all accounts.
+func (s *AuditStore) Prune(ctx context.Context, maxAge time.Duration) (int64, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM audit_log WHERE timestamp < ?`, time.Now().Add(-maxAge).Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+	return res.RowsAffected()
+}
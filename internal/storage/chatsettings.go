@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const chatSettingsSchema = `
+CREATE TABLE IF NOT EXISTS chat_settings (
+	account TEXT NOT NULL,
+	chat    TEXT NOT NULL,
+	key     TEXT NOT NULL,
+	value   TEXT NOT NULL,
+	PRIMARY KEY (account, chat, key)
+);
+CREATE TABLE IF NOT EXISTS disabled_commands (
+	account TEXT NOT NULL,
+	chat    TEXT NOT NULL,
+	name    TEXT NOT NULL,
+	PRIMARY KEY (account, chat, name)
+);
+`
+
+// GlobalChat is the chat key used for account-wide settings.
+const GlobalChat = "*"
+
+// ChatSettingsStore keeps small per-chat key/value settings as well as the
+// commands and categories disabled in each chat.
+type ChatSettingsStore struct {
+	db *DB
+}
+
+func (d *DB) ChatSettings() *ChatSettingsStore {
+	return &ChatSettingsStore{db: d}
+}
+
+func (s *ChatSettingsStore) Get(ctx context.Context, account, chat, key string) (string, bool, error) {
+	var value string
+	err := s.db.db.QueryRowContext(ctx, `
+		SELECT value FROM chat_settings WHERE account = ? AND chat = ? AND key = ?`,
+		account, chat, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("failed to query chat setting: %w", err)
+	}
+	return value, true, nil
+}
+
+func (s *ChatSettingsStore) Set(ctx context.Context, account, chat, key, value string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO chat_settings (account, chat, key, value) VALUES (?, ?, ?, ?)`,
+		account, chat, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to save chat setting: %w", err)
+	}
+	return nil
+}
+
+func (s *ChatSettingsStore) Delete(ctx context.Context, account, chat, key string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM chat_settings WHERE account = ? AND chat = ? AND key = ?`,
+		account, chat, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat setting: %w", err)
+	}
+	return nil
+}
+
+// ChatsWith returns every chat that has key set, mapped to its value.
+func (s *ChatSettingsStore) ChatsWith(ctx context.Context, account, key string) (map[string]string, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT chat, value FROM chat_settings WHERE account = ? AND key = ? AND chat != ?`,
+		account, key, GlobalChat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat settings: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var chat, value string
+		if err := rows.Scan(&chat, &value); err != nil {
+			return nil, fmt.Errorf("failed to read chat setting: %w", err)
+		}
+		result[chat] = value
+	}
+	return result, rows.Err()
+}
+
+// Disable turns off a command or category (by lowercase name) in a chat.
+func (s *ChatSettingsStore) Disable(ctx context.Context, account, chat, name string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO disabled_commands (account, chat, name) VALUES (?, ?, ?)`,
+		account, chat, name)
+	if err != nil {
+		return fmt.Errorf("failed to disable %s: %w", name, err)
+	}
+	return nil
+}
+
+// Enable reverts Disable and reports whether the name was disabled.
+func (s *ChatSettingsStore) Enable(ctx context.Context, account, chat, name string) (bool, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM disabled_commands WHERE account = ? AND chat = ? AND name = ?`,
+		account, chat, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to enable %s: %w", name, err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *ChatSettingsStore) Disabled(ctx context.Context, account, chat string) ([]string, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT name FROM disabled_commands WHERE account = ? AND chat = ? ORDER BY name`,
+		account, chat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query disabled commands: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read disabled command: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const downloadsSchema = `
+CREATE TABLE IF NOT EXISTS downloads (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	account    TEXT    NOT NULL,
+	chat       TEXT    NOT NULL,
+	sender     TEXT    NOT NULL,
+	sender_alt TEXT    NOT NULL DEFAULT '',
+	from_me    INTEGER NOT NULL DEFAULT 0,
+	message_id TEXT    NOT NULL,
+	status_id  TEXT    NOT NULL DEFAULT '',
+	request    TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS downloads_account ON downloads (account, id);
+`
+
+// Download is a queued or running /download, kept so it can be resumed
+// after a restart. MessageID is the command message and StatusID the
+// message showing its progress; Request is the command's encoded options.
+type Download struct {
+	ID        int64
+	Chat      string
+	Sender    string
+	SenderAlt string
+	FromMe    bool
+	MessageID string
+	StatusID  string
+	Request   string
+	CreatedAt time.Time
+}
+
+type DownloadStore struct {
+	db *DB
+}
+
+func (d *DB) Downloads() *DownloadStore {
+	return &DownloadStore{db: d}
+}
+
+// Add stores a download and returns its ID.
+func (s *DownloadStore) Add(ctx context.Context, account string, dl Download) (int64, error) {
+	if dl.CreatedAt.IsZero() {
+		dl.CreatedAt = time.Now()
+	}
+	res, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO downloads (account, chat, sender, sender_alt, from_me, message_id, status_id, request, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account, dl.Chat, dl.Sender, dl.SenderAlt, dl.FromMe, dl.MessageID, dl.StatusID, dl.Request, dl.CreatedAt.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to save download: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// List returns the downloads of account in the order they were queued.
+func (s *DownloadStore) List(ctx context.Context, account string) ([]Download, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT id, chat, sender, sender_alt, from_me, message_id, status_id, request, created_at
+		FROM downloads WHERE account = ? ORDER BY id`, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query downloads: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Download
+	for rows.Next() {
+		var dl Download
+		var createdAt int64
+		if err := rows.Scan(&dl.ID, &dl.Chat, &dl.Sender, &dl.SenderAlt, &dl.FromMe, &dl.MessageID,
+			&dl.StatusID, &dl.Request, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read download: %w", err)
+		}
+		dl.CreatedAt = time.Unix(createdAt, 0)
+		result = append(result, dl)
+	}
+	return result, rows.Err()
+}
+
+// SetStatus records the status message of a download.
+func (s *DownloadStore) SetStatus(ctx context.Context, account string, id int64, statusID string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		UPDATE downloads SET status_id = ? WHERE account = ? AND id = ?`, statusID, account, id)
+	if err != nil {
+		return fmt.Errorf("failed to update download: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a download.
+func (s *DownloadStore) Delete(ctx context.Context, account string, id int64) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM downloads WHERE account = ? AND id = ?`, account, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete download: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const messagesSchema = `
+CREATE TABLE IF NOT EXISTS messages (
+	account   TEXT    NOT NULL,
+	chat      TEXT    NOT NULL,
+	id        TEXT    NOT NULL,
+	sender    TEXT    NOT NULL,
+	text      TEXT    NOT NULL,
+	timestamp INTEGER NOT NULL,
+	PRIMARY KEY (account, chat, id)
+);
+CREATE INDEX IF NOT EXISTS messages_timestamp ON messages (timestamp);
+`
+
+// ErrMessageNotFound is returned when a message isn't (or is no longer) in
+// the message store.
+var ErrMessageNotFound = errors.New("message not found")
+
+// Message is the text content of a message seen by the bot, kept so that
+// features triggered later (e.g. reactions) can refer back to it.
+type Message struct {
+	Chat      string
+	ID        string
+	Sender    string
+	Text      string
+	Timestamp time.Time
+}
+
+type MessageStore struct {
+	db *DB
+}
+
+func (d *DB) Messages() *MessageStore {
+	return &MessageStore{db: d}
+}
+
+func (s *MessageStore) Save(ctx context.Context, account string, m Message) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO messages (account, chat, id, sender, text, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		account, m.Chat, m.ID, m.Sender, m.Text, m.Timestamp.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+	return nil
+}
+
+// UpdateText replaces the stored text of an edited message.
+func (s *MessageStore) UpdateText(ctx context.Context, account, chat, id, text string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		UPDATE messages SET text = ? WHERE account = ? AND chat = ? AND id = ?`,
+		text, account, chat, id)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	return nil
+}
+
+func (s *MessageStore) Get(ctx context.Context, account, chat, id string) (Message, error) {
+	var m Message
+	var timestamp int64
+	err := s.db.db.QueryRowContext(ctx, `
+		SELECT chat, id, sender, text, timestamp FROM messages
+		WHERE account = ? AND chat = ? AND id = ?`,
+		account, chat, id).Scan(&m.Chat, &m.ID, &m.Sender, &m.Text, &timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Message{}, ErrMessageNotFound
+	} else if err != nil {
+		return Message{}, fmt.Errorf("failed to query message: %w", err)
+	}
+	m.Timestamp = time.Unix(timestamp, 0)
+	return m, nil
+}
+
+func (s *MessageStore) Delete(ctx context.Context, account, chat, id string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM messages WHERE account = ? AND chat = ? AND id = ?`,
+		account, chat, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	return nil
+}
+
+// Prune drops messages older than maxAge for all accounts.
+func (s *MessageStore) Prune(ctx context.Context, maxAge time.Duration) (int64, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM messages WHERE timestamp < ?`, time.Now().Add(-maxAge).Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune messages: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const rateLimitsSchema = `
+CREATE TABLE IF NOT EXISTS rate_limits (
+	account TEXT    NOT NULL,
+	key     TEXT    NOT NULL,
+	tokens  REAL    NOT NULL,
+	updated INTEGER NOT NULL,
+	PRIMARY KEY (account, key)
+);
+`
+
+// RateLimitStore persists command rate limit buckets.
+type RateLimitStore struct {
+	db *DB
+}
+
+func (d *DB) RateLimits() *RateLimitStore {
+	return &RateLimitStore{db: d}
+}
+
+// Get returns the tokens left in a bucket and when it was last updated; ok
+// is false when the bucket isn't stored.
+func (s *RateLimitStore) Get(ctx context.Context, account, key string) (tokens float64, updated time.Time, ok bool, err error) {
+	var updatedMs int64
+	err = s.db.db.QueryRowContext(ctx, `
+		SELECT tokens, updated FROM rate_limits WHERE account = ? AND key = ?`,
+		account, key).Scan(&tokens, &updatedMs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, time.Time{}, false, nil
+	} else if err != nil {
+		return 0, time.Time{}, false, fmt.Errorf("failed to query rate limit: %w", err)
+	}
+	return tokens, time.UnixMilli(updatedMs), true, nil
+}
+
+func (s *RateLimitStore) Save(ctx context.Context, account, key string, tokens float64, updated time.Time) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO rate_limits (account, key, tokens, updated) VALUES (?, ?, ?, ?)`,
+		account, key, tokens, updated.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to save rate limit: %w", err)
+	}
+	return nil
+}
+
+// Prune drops buckets not used for maxAge for all accounts. Buckets refill
+// over time, so a bucket is only worth keeping while it may still be short.
+func (s *RateLimitStore) Prune(ctx context.Context, maxAge time.Duration) (int64, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM rate_limits WHERE updated < ?`, time.Now().Add(-maxAge).UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune rate limits: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const rolesSchema = `
+CREATE TABLE IF NOT EXISTS roles (
+	account    TEXT    NOT NULL,
+	jid        TEXT    NOT NULL,
+	role       INTEGER NOT NULL,
+	granted_by TEXT    NOT NULL DEFAULT '',
+	granted_at INTEGER NOT NULL,
+	PRIMARY KEY (account, jid)
+);
+`
+
+// RoleAssignment is a role granted to a user. Roles are stored as plain
+// integers so this package doesn't depend on the command framework.
+type RoleAssignment struct {
+	JID       string
+	Role      int
+	GrantedBy string
+	GrantedAt time.Time
+}
+
+type RoleStore struct {
+	db *DB
+}
+
+func (d *DB) Roles() *RoleStore {
+	return &RoleStore{db: d}
+}
+
+func (s *RoleStore) Set(ctx context.Context, account string, a RoleAssignment) error {
+	if a.GrantedAt.IsZero() {
+		a.GrantedAt = time.Now()
+	}
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO roles (account, jid, role, granted_by, granted_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		account, a.JID, a.Role, a.GrantedBy, a.GrantedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+	return nil
+}
+
+// Get returns the role assigned to jid; ok is false when none is.
+func (s *RoleStore) Get(ctx context.Context, account, jid string) (role int, ok bool, err error) {
+	err = s.db.db.QueryRowContext(ctx, `
+		SELECT role FROM roles WHERE account = ? AND jid = ?`,
+		account, jid).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("failed to query role: %w", err)
+	}
+	return role, true, nil
+}
+
+// Delete removes the role of jid and reports whether there was one.
+func (s *RoleStore) Delete(ctx context.Context, account, jid string) (bool, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM roles WHERE account = ? AND jid = ?`, account, jid)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete role: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// List returns all role assignments, highest role first.
+func (s *RoleStore) List(ctx context.Context, account string) ([]RoleAssignment, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT jid, role, granted_by, granted_at FROM roles
+		WHERE account = ? ORDER BY role DESC, jid`, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	defer rows.Close()
+
+	var result []RoleAssignment
+	for rows.Next() {
+		var a RoleAssignment
+		var grantedAt int64
+		if err := rows.Scan(&a.JID, &a.Role, &a.GrantedBy, &grantedAt); err != nil {
+			return nil, fmt.Errorf("failed to read role: %w", err)
+		}
+		a.GrantedAt = time.Unix(grantedAt, 0)
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const rulesSchema = `
+CREATE TABLE IF NOT EXISTS rules (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	account     TEXT    NOT NULL,
+	chat        TEXT    NOT NULL,
+	pattern     TEXT    NOT NULL,
+	regex       INTEGER NOT NULL DEFAULT 0,
+	reply       TEXT    NOT NULL,
+	translate   INTEGER NOT NULL DEFAULT 0,
+	cooldown    INTEGER NOT NULL DEFAULT 0,
+	window_from TEXT    NOT NULL DEFAULT '',
+	window_to   TEXT    NOT NULL DEFAULT '',
+	timezone    TEXT    NOT NULL DEFAULT '',
+	creator     TEXT    NOT NULL,
+	created_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rules_account ON rules (account, id);
+`
+
+// Chats of a Rule besides chat JIDs.
+const (
+	RuleChatDirect = "dm"
+	RuleChatAny    = "*"
+)
+
+// Rule is an auto-reply: messages in Chat matching Pattern (a keyword, or a
+// regular expression if Regex is set) are answered with Reply. WindowFrom
+// and WindowTo ("15:04", both empty for all day) limit the rule to a time
+// of day in Timezone, which is empty for the server's zone.
+type Rule struct {
+	ID         int64
+	Chat       string
+	Pattern    string
+	Regex      bool
+	Reply      string
+	Translate  bool
+	Cooldown   time.Duration
+	WindowFrom string
+	WindowTo   string
+	Timezone   string
+	Creator    string
+	CreatedAt  time.Time
+}
+
+type RuleStore struct {
+	db *DB
+}
+
+func (d *DB) Rules() *RuleStore {
+	return &RuleStore{db: d}
+}
+
+// Add stores a rule and returns its ID.
+func (s *RuleStore) Add(ctx context.Context, account string, r Rule) (int64, error) {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	res, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO rules (account, chat, pattern, regex, reply, translate, cooldown,
+			window_from, window_to, timezone, creator, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account, r.Chat, r.Pattern, r.Regex, r.Reply, r.Translate, int64(r.Cooldown/time.Second),
+		r.WindowFrom, r.WindowTo, r.Timezone, r.Creator, r.CreatedAt.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to save rule: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// List returns the rules of account, oldest first.
+func (s *RuleStore) List(ctx context.Context, account string) ([]Rule, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT id, chat, pattern, regex, reply, translate, cooldown,
+			window_from, window_to, timezone, creator, created_at
+		FROM rules WHERE account = ? ORDER BY id`, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+	return scanRules(rows)
+}
+
+// Delete removes a rule and reports whether it existed.
+func (s *RuleStore) Delete(ctx context.Context, account string, id int64) (bool, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM rules WHERE account = ? AND id = ?`, account, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanRules(rows *sql.Rows) ([]Rule, error) {
+	defer rows.Close()
+
+	var result []Rule
+	for rows.Next() {
+		var r Rule
+		var cooldown, createdAt int64
+		if err := rows.Scan(&r.ID, &r.Chat, &r.Pattern, &r.Regex, &r.Reply, &r.Translate, &cooldown,
+			&r.WindowFrom, &r.WindowTo, &r.Timezone, &r.Creator, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read rule: %w", err)
+		}
+		r.Cooldown = time.Duration(cooldown) * time.Second
+		r.CreatedAt = time.Unix(createdAt, 0)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
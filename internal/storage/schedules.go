@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const schedulesSchema = `
+CREATE TABLE IF NOT EXISTS schedules (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	account      TEXT    NOT NULL,
+	kind         TEXT    NOT NULL,
+	chat         TEXT    NOT NULL,
+	creator      TEXT    NOT NULL,
+	text         TEXT    NOT NULL,
+	lang         TEXT    NOT NULL DEFAULT '',
+	repeat       TEXT    NOT NULL DEFAULT '',
+	timezone     TEXT    NOT NULL DEFAULT '',
+	quote_id     TEXT    NOT NULL DEFAULT '',
+	quote_sender TEXT    NOT NULL DEFAULT '',
+	quote_text   TEXT    NOT NULL DEFAULT '',
+	next_run     INTEGER NOT NULL,
+	created_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS schedules_account_next_run ON schedules (account, next_run);
+`
+
+// Repeat intervals of a Schedule.
+const (
+	RepeatNone   = ""
+	RepeatDaily  = "daily"
+	RepeatWeekly = "weekly"
+)
+
+// Schedule is a message the bot sends later. Kind tells the scheduler how
+// to deliver it; Timezone is the IANA name of the zone recurring schedules
+// keep their wall clock time in, empty for the server's zone. The Quote
+// fields name a message the delivered message replies to.
+type Schedule struct {
+	ID          int64
+	Kind        string
+	Chat        string
+	Creator     string
+	Text        string
+	Lang        string
+	Repeat      string
+	Timezone    string
+	QuoteID     string
+	QuoteSender string
+	QuoteText   string
+	NextRun     time.Time
+	CreatedAt   time.Time
+}
+
+// ScheduleQuery filters schedules; empty fields match everything.
+type ScheduleQuery struct {
+	Kind    string
+	Chat    string
+	Creator string
+}
+
+type ScheduleStore struct {
+	db *DB
+}
+
+func (d *DB) Schedules() *ScheduleStore {
+	return &ScheduleStore{db: d}
+}
+
+// Add stores a schedule and returns its ID.
+func (s *ScheduleStore) Add(ctx context.Context, account string, sc Schedule) (int64, error) {
+	if sc.CreatedAt.IsZero() {
+		sc.CreatedAt = time.Now()
+	}
+	res, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO schedules (account, kind, chat, creator, text, lang, repeat, timezone,
+			quote_id, quote_sender, quote_text, next_run, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account, sc.Kind, sc.Chat, sc.Creator, sc.Text, sc.Lang, sc.Repeat, sc.Timezone,
+		sc.QuoteID, sc.QuoteSender, sc.QuoteText, sc.NextRun.Unix(), sc.CreatedAt.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func (s *ScheduleStore) Get(ctx context.Context, account string, id int64) (Schedule, bool, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT `+scheduleColumns+` FROM schedules WHERE account = ? AND id = ?`, account, id)
+	if err != nil {
+		return Schedule{}, false, fmt.Errorf("failed to query schedule: %w", err)
+	}
+	result, err := scanSchedules(rows)
+	if err != nil || len(result) == 0 {
+		return Schedule{}, false, err
+	}
+	return result[0], true, nil
+}
+
+// List returns the schedules matching q, soonest first.
+func (s *ScheduleStore) List(ctx context.Context, account string, q ScheduleQuery) ([]Schedule, error) {
+	where := []string{"account = ?"}
+	args := []any{account}
+	for _, filter := range []struct{ column, value string }{
+		{"kind", q.Kind},
+		{"chat", q.Chat},
+		{"creator", q.Creator},
+	} {
+		if filter.value != "" {
+			where = append(where, filter.column+" = ?")
+			args = append(args, filter.value)
+		}
+	}
+
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT `+scheduleColumns+` FROM schedules WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY next_run, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	return scanSchedules(rows)
+}
+
+// Due returns the schedules whose time has come, oldest first.
+func (s *ScheduleStore) Due(ctx context.Context, account string, now time.Time) ([]Schedule, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT `+scheduleColumns+` FROM schedules WHERE account = ? AND next_run <= ?
+		ORDER BY next_run, id`, account, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due schedules: %w", err)
+	}
+	return scanSchedules(rows)
+}
+
+// Reschedule moves a recurring schedule to its next run.
+func (s *ScheduleStore) Reschedule(ctx context.Context, account string, id int64, next time.Time) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		UPDATE schedules SET next_run = ? WHERE account = ? AND id = ?`, next.Unix(), account, id)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a schedule and reports whether it existed.
+func (s *ScheduleStore) Delete(ctx context.Context, account string, id int64) (bool, error) {
+	res, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM schedules WHERE account = ? AND id = ?`, account, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+const scheduleColumns = `id, kind, chat, creator, text, lang, repeat, timezone,
+	quote_id, quote_sender, quote_text, next_run, created_at`
+
+func scanSchedules(rows *sql.Rows) ([]Schedule, error) {
+	defer rows.Close()
+
+	var result []Schedule
+	for rows.Next() {
+		var sc Schedule
+		var nextRun, createdAt int64
+		if err := rows.Scan(&sc.ID, &sc.Kind, &sc.Chat, &sc.Creator, &sc.Text, &sc.Lang, &sc.Repeat,
+			&sc.Timezone, &sc.QuoteID, &sc.QuoteSender, &sc.QuoteText, &nextRun, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read schedule: %w", err)
+		}
+		sc.NextRun = time.Unix(nextRun, 0)
+		sc.CreatedAt = time.Unix(createdAt, 0)
+		result = append(result, sc)
+	}
+	return result, rows.Err()
+}
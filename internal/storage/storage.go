@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// schema is applied in order every time the database is opened, so every
+// statement must be idempotent.
+var schema = []string{
+	translationsSchema,
+	messagesSchema,
+	rolesSchema,
+	chatSettingsSchema,
+	rateLimitsSchema,
+	auditSchema,
+	uiStringsSchema,
+	schedulesSchema,
+	rulesSchema,
+	downloadsSchema,
+}
+
+// DB is the bot's own database. It is kept separate from the whatsmeow
+// session store and shared by all accounts; every table is scoped by an
+// account column holding the account's phone number.
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens (creating if needed) the SQLite database at dsn and brings its
+// schema up to date. The sqlite3 driver must be registered by the caller.
+func Open(dsn string) (*DB, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// SQLite only allows one writer; serializing access avoids "database is
+	// locked" errors under concurrent handlers.
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to apply schema: %w", err)
+		}
+	}
+	return &DB{db: db}, nil
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
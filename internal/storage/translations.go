@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const translationsSchema = `
+CREATE TABLE IF NOT EXISTS translations (
+	account        TEXT    NOT NULL,
+	chat           TEXT    NOT NULL,
+	source_id      TEXT    NOT NULL,
+	translation_id TEXT    NOT NULL,
+	target_lang    TEXT    NOT NULL,
+	inline         INTEGER NOT NULL DEFAULT 0,
+	created_at     INTEGER NOT NULL,
+	PRIMARY KEY (account, chat, source_id, target_lang)
+);
+CREATE INDEX IF NOT EXISTS translations_translation_id ON translations (account, chat, translation_id);
+`
+
+// Translation links a message to the message carrying its translation.
+// Inline is set when the source is the command itself ("/hi text"), so the
+// command token has to be stripped before re-translating.
+type Translation struct {
+	Chat          string
+	SourceID      string
+	TranslationID string
+	TargetLang    string
+	Inline        bool
+	CreatedAt     time.Time
+}
+
+type TranslationStore struct {
+	db *DB
+}
+
+func (d *DB) Translations() *TranslationStore {
+	return &TranslationStore{db: d}
+}
+
+// Save records a translation, replacing any earlier one of the same source
+// into the same language.
+func (s *TranslationStore) Save(ctx context.Context, account string, t Translation) error {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO translations (account, chat, source_id, translation_id, target_lang, inline, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		account, t.Chat, t.SourceID, t.TranslationID, t.TargetLang, t.Inline, t.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+// BySource returns every translation made of the given message.
+func (s *TranslationStore) BySource(ctx context.Context, account, chat, sourceID string) ([]Translation, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT chat, source_id, translation_id, target_lang, inline, created_at
+		FROM translations WHERE account = ? AND chat = ? AND source_id = ?`,
+		account, chat, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query translations: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Translation
+	for rows.Next() {
+		var t Translation
+		var createdAt int64
+		if err := rows.Scan(&t.Chat, &t.SourceID, &t.TranslationID, &t.TargetLang, &t.Inline, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read translation: %w", err)
+		}
+		t.CreatedAt = time.Unix(createdAt, 0)
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+// IsTranslation reports whether the message was sent (or edited) by the bot
+// as a translation.
+func (s *TranslationStore) IsTranslation(ctx context.Context, account, chat, messageID string) (bool, error) {
+	var exists bool
+	err := s.db.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM translations WHERE account = ? AND chat = ? AND translation_id = ?)`,
+		account, chat, messageID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to query translations: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteBySource forgets every translation of the given message.
+func (s *TranslationStore) DeleteBySource(ctx context.Context, account, chat, sourceID string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM translations WHERE account = ? AND chat = ? AND source_id = ?`,
+		account, chat, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete translations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const uiStringsSchema = `
+CREATE TABLE IF NOT EXISTS ui_strings (
+	account TEXT NOT NULL,
+	lang    TEXT NOT NULL,
+	key     TEXT NOT NULL,
+	source  TEXT NOT NULL,
+	text    TEXT NOT NULL,
+	PRIMARY KEY (account, lang, key)
+);
+`
+
+// UIStringStore caches machine translations of the bot's replies. source is
+// the English text that was translated, so stale entries can be recognized.
+type UIStringStore struct {
+	db *DB
+}
+
+func (d *DB) UIStrings() *UIStringStore {
+	return &UIStringStore{db: d}
+}
+
+func (s *UIStringStore) Get(ctx context.Context, account, lang, key string) (source, text string, ok bool, err error) {
+	err = s.db.db.QueryRowContext(ctx, `
+		SELECT source, text FROM ui_strings WHERE account = ? AND lang = ? AND key = ?`,
+		account, lang, key).Scan(&source, &text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", false, nil
+	} else if err != nil {
+		return "", "", false, fmt.Errorf("failed to query UI string: %w", err)
+	}
+	return source, text, true, nil
+}
+
+func (s *UIStringStore) Save(ctx context.Context, account, lang, key, source, text string) error {
+	_, err := s.db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO ui_strings (account, lang, key, source, text) VALUES (?, ?, ?, ?, ?)`,
+		account, lang, key, source, text)
+	if err != nil {
+		return fmt.Errorf("failed to save UI string: %w", err)
+	}
+	return nil
+}
@@ -15,3 +15,12 @@ func GetLangByCode(code string) lingua.Language {
 		return lang
 	}
 }
+
+// GetCodeByLang returns the lowercase ISO 639-1 code of lang, or "" for
+// lingua.Unknown.
+func GetCodeByLang(lang lingua.Language) string {
+	if lang == lingua.Unknown {
+		return ""
+	}
+	return strings.ToLower(lang.IsoCode639_1().String())
+}
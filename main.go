@@ -2,56 +2,131 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	// Timezones for /timezone even where the system has no zoneinfo
+	_ "time/tzdata"
 
 	"github.com/asparkoffire/whatsapp-livetranslate-go/config"
+	framework "github.com/asparkoffire/whatsapp-livetranslate-go/internal/cmdframework"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/constants"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/logging"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/server"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services"
-	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/messagehandler"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/dispatcher"
 	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/openrouter"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/services/session"
+	"github.com/asparkoffire/whatsapp-livetranslate-go/internal/storage"
 	_ "github.com/mattn/go-sqlite3"
-	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 )
 
 func main() {
+	logging.Setup(config.AppConfig.LogLevel, config.AppConfig.LogFormat,
+		config.AppConfig.GeminiAPIKey, config.AppConfig.OpenrouterApiKey, config.AppConfig.APIToken)
+
 	ctx := context.Background()
 	container, err := sqlstore.New(ctx, "sqlite3", "file:./data/auth.db?_foreign_keys=on", nil)
 	if err != nil {
-		log.Fatalf("error while opening a database connection: %v\n", err)
+		logging.Log.Fatal().Err(err).Msg("Error while opening a database connection")
 		return
 	}
 
-	deviceStore, err := container.GetFirstDevice(ctx)
+	db, err := storage.Open("file:./data/bot.db?_foreign_keys=on")
 	if err != nil {
-		log.Fatalf("error while getting the device store : %v\n", err)
+		logging.Log.Fatal().Err(err).Msg("Error while opening the bot database")
 		return
 	}
-
-	client := whatsmeow.NewClient(deviceStore, nil)
-	translator := openrouter.NewOpenrouterTranslator(config.AppConfig.OpenrouterModel, config.AppConfig.OpenrouterBaseUrl, config.AppConfig.OpenrouterApiKey)
-	imageGenerator := openrouter.NewOpenrouterImageGenerator(config.AppConfig.OpenrouterImageModel, config.AppConfig.OpenrouterBaseUrl, config.AppConfig.OpenrouterApiKey)
+	defer db.Close()
 
 	// Initialize the language detector with supported languages
 	detector := services.NewLinguaLangDetectService(constants.SupportedLanguages)
 
-	// connect to the client and event handler
-	evtHandler, err := messagehandler.NewWhatsMeowEventHandler(client, detector, translator, imageGenerator)
-	if err != nil {
-		log.Fatalf("error while setting up the event handler: %v\n", err)
+	// message handling runs on a shared, bounded pool that keeps each chat in
+	// order
+	commands := dispatcher.New(config.AppConfig.CommandWorkers, config.AppConfig.CommandQueueDepth)
+
+	// every account gets its own translator and image generator so that model
+	// and temperature changes stay local to it
+	manager := session.NewManager(container, session.Options{
+		DB:       db,
+		Detector: detector,
+		NewTranslator: func() services.TranslateService {
+			return openrouter.NewOpenrouterTranslator(config.AppConfig.OpenrouterModel, config.AppConfig.OpenrouterBaseUrl, config.AppConfig.OpenrouterApiKey)
+		},
+		NewImageGenerator: func() services.ImageGenerator {
+			return openrouter.NewOpenrouterImageGenerator(config.AppConfig.OpenrouterImageModel, config.AppConfig.OpenrouterBaseUrl, config.AppConfig.OpenrouterApiKey)
+		},
+		Dispatcher: commands,
+	})
+
+	if err := manager.LoadAll(ctx); err != nil {
+		logging.Log.Fatal().Err(err).Msg("Error while starting the sessions")
 		return
 	}
 
-	client.AddEventHandler(evtHandler.HandleEvents)
-	fmt.Println("Server started, Listening for messages...")
+	go pruneStorage(db)
+
+	httpServer := server.NewServer(config.AppConfig.HTTPAddr)
+	server.RegisterHealthRoutes(httpServer, manager, config.AppConfig.DisconnectGrace)
+	if config.AppConfig.APIToken == "" {
+		logging.Log.Warn().Msg("API_TOKEN not set, pairing and account routes only answer requests from this machine")
+	}
+	server.RegisterPairingRoutes(httpServer, manager, config.AppConfig.APIToken)
+	server.RegisterAccountRoutes(httpServer, manager, config.AppConfig.APIToken)
+	if config.AppConfig.APIToken != "" {
+		server.RegisterAPIRoutes(httpServer, manager, config.AppConfig.APIToken)
+	} else {
+		logging.Log.Warn().Msg("API_TOKEN not set, REST API disabled")
+	}
+	httpServer.Start()
+	logging.Log.Info().Msg("Server started, listening for messages")
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 
-	client.Disconnect()
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		logging.Log.Error().Err(err).Msg("Error while shutting down the HTTP server")
+	}
+
+	// let in-flight commands finish while the clients can still reply
+	drainCtx, cancelDrain := context.WithTimeout(ctx, config.AppConfig.DrainTimeout)
+	defer cancelDrain()
+	if err := commands.Drain(drainCtx); err != nil {
+		logging.Log.Warn().Err(err).Msg("Error while waiting for commands to finish")
+	}
+	if err := framework.WaitAbandoned(drainCtx); err != nil {
+		logging.Log.Warn().Err(err).Msg("Error while waiting for timed out commands to finish")
+	}
+
+	manager.DisconnectAll()
+}
+
+// pruneStorage periodically drops stored messages older than
+// MESSAGE_RETENTION, audit entries of the same age and rate limit buckets
+// unused for a day.
+func pruneStorage(db *storage.DB) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		removed, err := db.Messages().Prune(context.Background(), config.AppConfig.MessageRetention)
+		if err != nil {
+			logging.Log.Error().Err(err).Msg("Error while pruning stored messages")
+		} else if removed > 0 {
+			logging.Log.Info().Int64("removed", removed).Msg("Pruned stored messages")
+		}
+
+		if _, err := db.RateLimits().Prune(context.Background(), 24*time.Hour); err != nil {
+			logging.Log.Error().Err(err).Msg("Error while pruning rate limits")
+		}
+
+		if _, err := db.Audit().Prune(context.Background(), config.AppConfig.MessageRetention); err != nil {
+			logging.Log.Error().Err(err).Msg("Error while pruning the audit log")
+		}
+	}
 }